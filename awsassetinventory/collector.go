@@ -4,11 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ConfigClient defines the interface for AWS Config operations.
@@ -18,19 +25,45 @@ type ConfigClient interface {
 	GetDiscoveredResourceCounts(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error)
 }
 
+// AggregateCountsClient defines the AWS Config aggregator operation used for
+// counts-only collection. Clients returned by a ConfigClientFactory may
+// optionally implement it.
+type AggregateCountsClient interface {
+	GetAggregateDiscoveredResourceCounts(ctx context.Context, params *configservice.GetAggregateDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetAggregateDiscoveredResourceCountsOutput, error)
+}
+
+// ResourceTagsClient defines the AWS Config query operation used to collect
+// resource tags, which BatchGetResourceConfig does not return. Clients
+// returned by a ConfigClientFactory may optionally implement it; without it,
+// resources are collected without tags.
+type ResourceTagsClient interface {
+	SelectResourceConfig(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error)
+}
+
 // ConfigClientFactory creates ConfigClient instances for specific regions.
 type ConfigClientFactory func(region Region) ConfigClient
 
-// Logger is a callback for progress messages.
-type Logger func(format string, args ...any)
-
 // Collector gathers AWS resources from AWS Config across regions.
+//
+// Progress is reported as typed events to Observer, and as structured log
+// records to Logger; either or both may be nil.
 type Collector struct {
 	profile        string
 	clientFactory  ConfigClientFactory
-	Logger         Logger
-	MaxConcurrency int // 0 means use default (5)
-	MaxRetries     int // 0 means use default (3)
+	Logger         *slog.Logger
+	Observer       Observer
+	MaxConcurrency int           // 0 means use default (5)
+	MaxRetries     int           // 0 means use default (3)
+	RegionTimeout  time.Duration // 0 means no per-region deadline
+
+	// TracerProvider and MeterProvider receive OpenTelemetry spans and
+	// metrics for each run, region, resource type and AWS call. When nil,
+	// the global providers are used, which are no-ops unless configured.
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+
+	instrumentsOnce sync.Once
+	instruments     *instruments
 }
 
 func (c *Collector) maxConcurrency() int {
@@ -47,6 +80,69 @@ func (c *Collector) maxRetries() int {
 	return DefaultMaxRetries
 }
 
+// emit delivers a progress event to the Observer and Logger.
+func (c *Collector) emit(e Event) {
+	e.Time = time.Now().UTC()
+	if c.Observer != nil {
+		c.Observer.OnEvent(e)
+	}
+	c.logEvent(e)
+}
+
+// retryNotifier returns a retry callback that reports throttling and retries
+// of an operation as events and metrics for the given region and resource type.
+func (c *Collector) retryNotifier(operation string, region Region, resourceType ResourceType) retryNotifyFunc {
+	return func(attempt int, err error, delay time.Duration) {
+		c.recordThrottle(operation, region)
+		c.emit(Event{Kind: EventThrottled, Region: region, ResourceType: resourceType, Attempt: attempt, RequestID: requestIDFromError(err), Err: err})
+		if delay > 0 {
+			c.emit(Event{Kind: EventRetry, Region: region, ResourceType: resourceType, Attempt: attempt + 1, Delay: delay, Err: err})
+		}
+	}
+}
+
+// callAWS invokes an AWS Config operation with retries. Each attempt is
+// traced, timed and logged along with its AWS request ID.
+func callAWS[T any](ctx context.Context, c *Collector, operation string, region Region, resourceType ResourceType, fn func(ctx context.Context) (T, middleware.Metadata, error)) (T, error) {
+	attempt := 0
+	return retryNotify(ctx, c.maxRetries(), c.retryNotifier(operation, region, resourceType), func() (T, error) {
+		attempt++
+		callCtx, span := c.startSpan(ctx, "AWSConfig."+operation, region, resourceType)
+		span.SetAttributes(attribute.Int("retry.attempt", attempt))
+
+		start := time.Now()
+		output, metadata, err := fn(callCtx)
+		c.recordCallDuration(callCtx, operation, region, time.Since(start), err)
+
+		requestID := requestIDOf(metadata, err)
+		if requestID != "" {
+			span.SetAttributes(attribute.String("aws.request_id", requestID))
+		}
+		c.logCall(callCtx, operation, region, resourceType, attempt, requestID, err)
+		endSpan(span, err)
+		return output, err
+	})
+}
+
+// withObserver returns a copy of c that reports events to o. The copy
+// shares c's OpenTelemetry instruments.
+func (c *Collector) withObserver(o Observer) *Collector {
+	cp := &Collector{
+		profile:        c.profile,
+		clientFactory:  c.clientFactory,
+		Logger:         c.Logger,
+		Observer:       o,
+		MaxConcurrency: c.MaxConcurrency,
+		MaxRetries:     c.MaxRetries,
+		RegionTimeout:  c.RegionTimeout,
+		TracerProvider: c.TracerProvider,
+		MeterProvider:  c.MeterProvider,
+	}
+	instruments := c.metrics()
+	cp.instrumentsOnce.Do(func() { cp.instruments = instruments })
+	return cp
+}
+
 // NewCollector creates a new Collector with the given AWS config and profile name.
 func NewCollector(profile string, clientFactory ConfigClientFactory) *Collector {
 	return &Collector{
@@ -59,35 +155,168 @@ func NewCollector(profile string, clientFactory ConfigClientFactory) *Collector
 type CollectResult struct {
 	Region    Region
 	Resources []Resource
+	Counts    []ResourceTypeCount
+	Failures  []ResourceFailure
 	Err       error
 }
 
 // Collect gathers all resources from AWS Config across the specified regions.
+//
+// When a region fails, or ctx is cancelled or times out, the resources gathered
+// so far are still returned alongside a CollectErrors, and the inventory is
+// marked Incomplete. Either way the inventory records each region's status
+// and any resources whose configuration could not be retrieved. Regions not
+// yet started when ctx is done are not started.
 func (c *Collector) Collect(ctx context.Context, regions []Region) (*Inventory, error) {
+	ctx, span := c.startRun(ctx, "Collect", regions)
+	inv, err := c.collect(ctx, regions)
+	endSpan(span, err)
+	return inv, err
+}
+
+func (c *Collector) collect(ctx context.Context, regions []Region) (*Inventory, error) {
 	inv := NewInventory(c.profile, regions)
 
-	resultCh := make(chan CollectResult, len(regions))
-	sem := make(chan struct{}, c.maxConcurrency())
-	var wg sync.WaitGroup
+	results := c.forEachRegion(ctx, regions, func(ctx context.Context, r Region) CollectResult {
+		ctx, span := c.startSpan(ctx, "CollectRegion", r, "")
+		c.emit(Event{Kind: EventRegionStarted, Region: r})
+		resources, counts, failures, err := c.collectRegion(ctx, r)
+		c.emit(Event{Kind: EventRegionCompleted, Region: r, Count: len(resources), Err: err})
+		span.SetAttributes(attribute.Int("asset_inventory.resources", len(resources)))
+		endSpan(span, err)
+		return CollectResult{Region: r, Resources: resources, Counts: counts, Failures: failures, Err: err}
+	})
 
-	for _, region := range regions {
-		wg.Add(1)
-		go func(r Region) {
-			defer wg.Done()
-			sem <- struct{}{}        // acquire semaphore
-			defer func() { <-sem }() // release semaphore
-			resources, err := c.collectRegion(ctx, r)
-			resultCh <- CollectResult{Region: r, Resources: resources, Err: err}
-		}(region)
+	var regionErrors []RegionError
+	for result := range results {
+		for _, r := range result.Resources {
+			inv.AddResource(r)
+		}
+		inv.Failures = append(inv.Failures, result.Failures...)
+		addRegionStatus(inv, result.Region, len(result.Resources), result.Err)
+		if result.Err != nil {
+			regionErrors = append(regionErrors, RegionError{
+				Region: result.Region,
+				Err:    result.Err,
+			})
+			continue
+		}
+		for _, count := range result.Counts {
+			inv.AddCount(count)
+		}
 	}
 
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+	inv.Mismatches = inv.Reconcile()
+	sortCollectionIssues(inv)
+
+	if len(regionErrors) > 0 {
+		inv.Incomplete = true
+		return inv, CollectErrors{Errors: regionErrors}
+	}
+
+	return inv, nil
+}
+
+// CollectCounts gathers per-type resource counts across the specified regions
+// using only GetDiscoveredResourceCounts. The returned inventory is counts-only
+// and holds no individual resources.
+func (c *Collector) CollectCounts(ctx context.Context, regions []Region) (*Inventory, error) {
+	ctx, span := c.startRun(ctx, "CollectCounts", regions)
+
+	inv := NewInventory(c.profile, regions)
+	inv.CountsOnly = true
+
+	results := c.forEachRegion(ctx, regions, func(ctx context.Context, r Region) CollectResult {
+		return c.countRegionResult(ctx, r, c.countRegion)
+	})
+
+	inv, err := collectCountResults(inv, results)
+	endSpan(span, err)
+	return inv, err
+}
+
+// CollectAggregateCounts gathers per-type resource counts for the specified
+// regions from the named AWS Config aggregator, which lives in aggregatorRegion.
+// Counts are summed across every account the aggregator covers. The client
+// created for aggregatorRegion must implement AggregateCountsClient.
+func (c *Collector) CollectAggregateCounts(ctx context.Context, aggregator string, aggregatorRegion Region, regions []Region) (*Inventory, error) {
+	ctx, span := c.startRun(ctx, "CollectAggregateCounts", regions)
+	span.SetAttributes(attribute.String("aws.config.aggregator", aggregator))
+	inv, err := c.collectAggregateCounts(ctx, aggregator, aggregatorRegion, regions)
+	endSpan(span, err)
+	return inv, err
+}
+
+func (c *Collector) collectAggregateCounts(ctx context.Context, aggregator string, aggregatorRegion Region, regions []Region) (*Inventory, error) {
+	inv := NewInventory(c.profile, regions)
+	inv.CountsOnly = true
+
+	client := c.clientFactory(aggregatorRegion)
+	if client == nil {
+		return aggregatorFailed(inv, aggregatorRegion, fmt.Errorf("nil AWS Config client for region %s", aggregatorRegion))
+	}
+	aggClient, ok := client.(AggregateCountsClient)
+	if !ok {
+		return aggregatorFailed(inv, aggregatorRegion, fmt.Errorf("AWS Config client for region %s does not support aggregator queries", aggregatorRegion))
+	}
+
+	results := c.forEachRegion(ctx, regions, func(ctx context.Context, r Region) CollectResult {
+		return c.countRegionResult(ctx, r, func(ctx context.Context, r Region) ([]ResourceTypeCount, error) {
+			return c.countAggregateRegion(ctx, aggClient, aggregator, r)
+		})
+	})
 
+	return collectCountResults(inv, results)
+}
+
+// aggregatorFailed marks inv incomplete when the aggregator cannot be
+// queried, recording the aggregator region and every requested region,
+// none of which could be counted, as failed with err.
+func aggregatorFailed(inv *Inventory, aggregatorRegion Region, err error) (*Inventory, error) {
+	inv.Incomplete = true
+	addRegionStatus(inv, aggregatorRegion, 0, err)
+	for _, r := range inv.Regions {
+		if r != aggregatorRegion {
+			addRegionStatus(inv, r, 0, err)
+		}
+	}
+	sortCollectionIssues(inv)
+	return inv, err
+}
+
+// startRun starts the root span for a collection run over regions.
+func (c *Collector) startRun(ctx context.Context, name string, regions []Region) (context.Context, trace.Span) {
+	return c.tracer().Start(ctx, name, trace.WithAttributes(
+		attribute.String("aws.profile", c.profile),
+		attribute.Int("aws.region.count", len(regions)),
+	))
+}
+
+// countRegionResult runs a counts-only collection for one region, reporting
+// progress and tracing it like a full region collection.
+func (c *Collector) countRegionResult(ctx context.Context, r Region, count func(context.Context, Region) ([]ResourceTypeCount, error)) CollectResult {
+	ctx, span := c.startSpan(ctx, "CountRegion", r, "")
+	c.emit(Event{Kind: EventRegionStarted, Region: r})
+	counts, err := count(ctx, r)
+	total := sumCounts(counts)
+	c.emit(Event{Kind: EventRegionCompleted, Region: r, Count: total, Err: err})
+	span.SetAttributes(attribute.Int("asset_inventory.resources", total))
+	endSpan(span, err)
+	return CollectResult{Region: r, Counts: counts, Err: err}
+}
+
+func sumCounts(counts []ResourceTypeCount) int {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	return total
+}
+
+func collectCountResults(inv *Inventory, results <-chan CollectResult) (*Inventory, error) {
 	var regionErrors []RegionError
-	for result := range resultCh {
+	for result := range results {
+		addRegionStatus(inv, result.Region, sumCounts(result.Counts), result.Err)
 		if result.Err != nil {
 			regionErrors = append(regionErrors, RegionError{
 				Region: result.Region,
@@ -95,58 +324,196 @@ func (c *Collector) Collect(ctx context.Context, regions []Region) (*Inventory,
 			})
 			continue
 		}
-		for _, r := range result.Resources {
-			inv.AddResource(r)
+		for _, count := range result.Counts {
+			inv.AddCount(count)
 		}
 	}
+	sortCollectionIssues(inv)
 
 	if len(regionErrors) > 0 {
+		inv.Incomplete = true
 		return inv, CollectErrors{Errors: regionErrors}
 	}
 
 	return inv, nil
 }
 
-func (c *Collector) collectRegion(ctx context.Context, region Region) ([]Resource, error) {
-	if c.Logger != nil {
-		c.Logger("[%s] Starting collection", region)
+// addRegionStatus records the outcome of collecting region.
+func addRegionStatus(inv *Inventory, region Region, resources int, err error) {
+	status := RegionStatus{Region: region, Status: RegionCollected, Resources: resources}
+	if err != nil {
+		status.Status = RegionFailed
+		status.Error = err.Error()
 	}
+	inv.RegionStatus = append(inv.RegionStatus, status)
+}
+
+// sortCollectionIssues puts region statuses in region order and failures
+// in region, type and ID order, as results arrive in no particular order.
+func sortCollectionIssues(inv *Inventory) {
+	sort.Slice(inv.RegionStatus, func(i, j int) bool {
+		return inv.RegionStatus[i].Region < inv.RegionStatus[j].Region
+	})
+	sort.Slice(inv.Failures, func(i, j int) bool {
+		a, b := inv.Failures[i], inv.Failures[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		return a.ResourceID < b.ResourceID
+	})
+}
 
+// forEachRegion runs fn for each region, bounded by MaxConcurrency, and
+// delivers the results on the returned channel, which is closed once every
+// region has finished. Once ctx is done, regions still waiting for a slot are
+// not started and report ctx.Err() instead. Each region runs under
+// RegionTimeout when it is set.
+func (c *Collector) forEachRegion(ctx context.Context, regions []Region, fn func(context.Context, Region) CollectResult) <-chan CollectResult {
+	resultCh := make(chan CollectResult, len(regions))
+	sem := make(chan struct{}, c.maxConcurrency())
+	var wg sync.WaitGroup
+
+	for _, region := range regions {
+		wg.Add(1)
+		go func(r Region) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}: // acquire semaphore
+			case <-ctx.Done():
+				resultCh <- CollectResult{Region: r, Err: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }() // release semaphore
+
+			if err := ctx.Err(); err != nil {
+				resultCh <- CollectResult{Region: r, Err: err}
+				return
+			}
+
+			regionCtx := ctx
+			if c.RegionTimeout > 0 {
+				var cancel context.CancelFunc
+				regionCtx, cancel = context.WithTimeout(ctx, c.RegionTimeout)
+				defer cancel()
+			}
+			resultCh <- fn(regionCtx, r)
+		}(region)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	return resultCh
+}
+
+func (c *Collector) countRegion(ctx context.Context, region Region) ([]ResourceTypeCount, error) {
 	client := c.clientFactory(region)
 	if client == nil {
 		return nil, fmt.Errorf("nil AWS Config client for region %s", region)
 	}
 
-	resourceTypes, err := c.discoverResourceTypes(ctx, client)
+	counts, err := c.discoverResourceCounts(ctx, client, region)
 	if err != nil {
 		return nil, err
 	}
 
-	if c.Logger != nil {
-		c.Logger("[%s] Found %d resource types", region, len(resourceTypes))
+	c.emit(Event{Kind: EventTypesDiscovered, Region: region, Count: len(counts)})
+
+	return counts, nil
+}
+
+func (c *Collector) countAggregateRegion(ctx context.Context, client AggregateCountsClient, aggregator string, region Region) ([]ResourceTypeCount, error) {
+	var counts []ResourceTypeCount
+	var nextToken *string
+
+	for {
+		input := &configservice.GetAggregateDiscoveredResourceCountsInput{
+			ConfigurationAggregatorName: aws.String(aggregator),
+			Filters: &types.ResourceCountFilters{
+				Region: aws.String(region.String()),
+			},
+			GroupByKey: types.ResourceCountGroupKeyResourceType,
+			NextToken:  nextToken,
+		}
+
+		output, err := callAWS(ctx, c, "GetAggregateDiscoveredResourceCounts", region, "", func(ctx context.Context) (*configservice.GetAggregateDiscoveredResourceCountsOutput, middleware.Metadata, error) {
+			output, err := client.GetAggregateDiscoveredResourceCounts(ctx, input)
+			if err != nil {
+				return nil, middleware.Metadata{}, err
+			}
+			return output, output.ResultMetadata, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range output.GroupedResourceCounts {
+			if aws.ToString(group.GroupName) == "" {
+				continue
+			}
+			counts = append(counts, ResourceTypeCount{
+				ResourceType: ResourceType(aws.ToString(group.GroupName)),
+				Region:       region,
+				Count:        int(group.ResourceCount),
+			})
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
 	}
 
+	c.emit(Event{Kind: EventTypesDiscovered, Region: region, Count: len(counts)})
+
+	return counts, nil
+}
+
+func (c *Collector) collectRegion(ctx context.Context, region Region) ([]Resource, []ResourceTypeCount, []ResourceFailure, error) {
+	client := c.clientFactory(region)
+	if client == nil {
+		return nil, nil, nil, fmt.Errorf("nil AWS Config client for region %s", region)
+	}
+
+	counts, err := c.discoverResourceCounts(ctx, client, region)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c.emit(Event{Kind: EventTypesDiscovered, Region: region, Count: len(counts)})
+
 	var resources []Resource
-	for _, rt := range resourceTypes {
-		rtResources, err := c.collectResourceType(ctx, client, region, rt)
+	var failures []ResourceFailure
+	for _, count := range counts {
+		rtCtx, span := c.startSpan(ctx, "CollectResourceType", region, count.ResourceType)
+		rtResources, rtFailures, err := c.collectResourceType(rtCtx, client, region, types.ResourceType(count.ResourceType))
+		span.SetAttributes(attribute.Int("asset_inventory.resources", len(rtResources)))
+		endSpan(span, err)
+		failures = append(failures, rtFailures...)
 		if err != nil {
-			return resources, err
+			return append(resources, rtResources...), counts, failures, err
 		}
-		if c.Logger != nil && len(rtResources) > 0 {
-			c.Logger("[%s] Collected %d %s", region, len(rtResources), rt)
+		if tc, ok := client.(ResourceTagsClient); ok && len(rtResources) > 0 {
+			if err := c.collectTags(rtCtx, tc, region, count.ResourceType, rtResources); err != nil {
+				c.logger().Warn("could not retrieve tags; resources are kept without them",
+					"region", region.String(), "resource_type", count.ResourceType.String(), "error", err)
+			}
 		}
+		c.recordResourcesCollected(ctx, region, count.ResourceType, len(rtResources))
+		c.emit(Event{Kind: EventTypeCollected, Region: region, ResourceType: count.ResourceType, Count: len(rtResources)})
 		resources = append(resources, rtResources...)
 	}
 
-	if c.Logger != nil {
-		c.Logger("[%s] Completed with %d resources", region, len(resources))
-	}
-
-	return resources, nil
+	return resources, counts, failures, nil
 }
 
-func (c *Collector) discoverResourceTypes(ctx context.Context, client ConfigClient) ([]types.ResourceType, error) {
-	var resourceTypes []types.ResourceType
+func (c *Collector) discoverResourceCounts(ctx context.Context, client ConfigClient, region Region) ([]ResourceTypeCount, error) {
+	var counts []ResourceTypeCount
 	var nextToken *string
 
 	for {
@@ -154,8 +521,12 @@ func (c *Collector) discoverResourceTypes(ctx context.Context, client ConfigClie
 			NextToken: nextToken,
 		}
 
-		output, err := retry(ctx, c.maxRetries(), func() (*configservice.GetDiscoveredResourceCountsOutput, error) {
-			return client.GetDiscoveredResourceCounts(ctx, input)
+		output, err := callAWS(ctx, c, "GetDiscoveredResourceCounts", region, "", func(ctx context.Context) (*configservice.GetDiscoveredResourceCountsOutput, middleware.Metadata, error) {
+			output, err := client.GetDiscoveredResourceCounts(ctx, input)
+			if err != nil {
+				return nil, middleware.Metadata{}, err
+			}
+			return output, output.ResultMetadata, nil
 		})
 		if err != nil {
 			return nil, err
@@ -163,7 +534,11 @@ func (c *Collector) discoverResourceTypes(ctx context.Context, client ConfigClie
 
 		for _, count := range output.ResourceCounts {
 			if count.ResourceType != "" {
-				resourceTypes = append(resourceTypes, count.ResourceType)
+				counts = append(counts, ResourceTypeCount{
+					ResourceType: ResourceType(count.ResourceType),
+					Region:       region,
+					Count:        int(count.Count),
+				})
 			}
 		}
 
@@ -173,11 +548,15 @@ func (c *Collector) discoverResourceTypes(ctx context.Context, client ConfigClie
 		nextToken = output.NextToken
 	}
 
-	return resourceTypes, nil
+	return counts, nil
 }
 
-func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient, region Region, resourceType types.ResourceType) ([]Resource, error) {
+// collectResourceType lists and describes every resource of resourceType in
+// region. Resources whose configuration cannot be retrieved are returned as
+// failures; when a whole batch fails they are kept with their listed details.
+func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient, region Region, resourceType types.ResourceType) ([]Resource, []ResourceFailure, error) {
 	var resources []Resource
+	var failures []ResourceFailure
 	var nextToken *string
 
 	for {
@@ -186,11 +565,15 @@ func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient
 			NextToken:    nextToken,
 		}
 
-		output, err := retry(ctx, c.maxRetries(), func() (*configservice.ListDiscoveredResourcesOutput, error) {
-			return client.ListDiscoveredResources(ctx, input)
+		output, err := callAWS(ctx, c, "ListDiscoveredResources", region, ResourceType(resourceType), func(ctx context.Context) (*configservice.ListDiscoveredResourcesOutput, middleware.Metadata, error) {
+			output, err := client.ListDiscoveredResources(ctx, input)
+			if err != nil {
+				return nil, middleware.Metadata{}, err
+			}
+			return output, output.ResultMetadata, nil
 		})
 		if err != nil {
-			return nil, err
+			return resources, failures, err
 		}
 
 		resourceKeys := make([]types.ResourceKey, 0, len(output.ResourceIdentifiers))
@@ -202,7 +585,7 @@ func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient
 		}
 
 		if len(resourceKeys) > 0 {
-			detailed, err := c.batchGetResources(ctx, client, region, resourceKeys)
+			detailed, unprocessed, err := c.batchGetResources(ctx, client, region, resourceKeys)
 			if err != nil {
 				for _, ri := range output.ResourceIdentifiers {
 					r := Resource{
@@ -212,9 +595,23 @@ func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient
 						Region:       region,
 					}
 					resources = append(resources, r)
+					failures = append(failures, ResourceFailure{
+						ResourceType: r.ResourceType,
+						ResourceID:   r.ResourceID,
+						Region:       region,
+						Reason:       fmt.Sprintf("configuration not retrieved: %v", err),
+					})
 				}
 			} else {
 				resources = append(resources, detailed...)
+				for _, key := range unprocessed {
+					failures = append(failures, ResourceFailure{
+						ResourceType: ResourceType(key.ResourceType),
+						ResourceID:   aws.ToString(key.ResourceId),
+						Region:       region,
+						Reason:       "configuration not retrieved: left unprocessed by BatchGetResourceConfig",
+					})
+				}
 			}
 		}
 
@@ -224,11 +621,14 @@ func (c *Collector) collectResourceType(ctx context.Context, client ConfigClient
 		nextToken = output.NextToken
 	}
 
-	return resources, nil
+	return resources, failures, nil
 }
 
-func (c *Collector) batchGetResources(ctx context.Context, client ConfigClient, region Region, keys []types.ResourceKey) ([]Resource, error) {
+// batchGetResources fetches the configuration of the resources with keys,
+// returning the keys AWS Config left unprocessed alongside them.
+func (c *Collector) batchGetResources(ctx context.Context, client ConfigClient, region Region, keys []types.ResourceKey) ([]Resource, []types.ResourceKey, error) {
 	var resources []Resource
+	var unprocessed []types.ResourceKey
 
 	for i := 0; i < len(keys); i += 100 {
 		end := i + 100
@@ -241,12 +641,17 @@ func (c *Collector) batchGetResources(ctx context.Context, client ConfigClient,
 			ResourceKeys: batch,
 		}
 
-		output, err := retry(ctx, c.maxRetries(), func() (*configservice.BatchGetResourceConfigOutput, error) {
-			return client.BatchGetResourceConfig(ctx, input)
+		output, err := callAWS(ctx, c, "BatchGetResourceConfig", region, ResourceType(batch[0].ResourceType), func(ctx context.Context) (*configservice.BatchGetResourceConfigOutput, middleware.Metadata, error) {
+			output, err := client.BatchGetResourceConfig(ctx, input)
+			if err != nil {
+				return nil, middleware.Metadata{}, err
+			}
+			return output, output.ResultMetadata, nil
 		})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		unprocessed = append(unprocessed, output.UnprocessedResourceKeys...)
 
 		for _, item := range output.BaseConfigurationItems {
 			var config json.RawMessage
@@ -259,7 +664,7 @@ func (c *Collector) batchGetResources(ctx context.Context, client ConfigClient,
 				ResourceID:       aws.ToString(item.ResourceId),
 				ResourceName:     aws.ToString(item.ResourceName),
 				Region:           region,
-				AvailabilityZone: aws.ToString(item.AvailabilityZone),
+				AvailabilityZone: zonalAvailabilityZone(aws.ToString(item.AvailabilityZone)),
 				AccountID:        aws.ToString(item.AccountId),
 				ARN:              aws.ToString(item.Arn),
 				Configuration:    config,
@@ -269,5 +674,65 @@ func (c *Collector) batchGetResources(ctx context.Context, client ConfigClient,
 		}
 	}
 
-	return resources, nil
+	return resources, unprocessed, nil
+}
+
+// selectedTags is a row of a SelectResourceConfig query for resource tags.
+type selectedTags struct {
+	ResourceID string `json:"resourceId"`
+	Tags       []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+// collectTags sets the Tags of resources, all of resourceType in region,
+// from an AWS Config query.
+func (c *Collector) collectTags(ctx context.Context, client ResourceTagsClient, region Region, resourceType ResourceType, resources []Resource) error {
+	byID := make(map[string]int, len(resources))
+	for i, r := range resources {
+		byID[r.ResourceID] = i
+	}
+
+	expression := fmt.Sprintf("SELECT resourceId, tags WHERE resourceType = '%s'", resourceType)
+	var nextToken *string
+	for {
+		input := &configservice.SelectResourceConfigInput{
+			Expression: aws.String(expression),
+			NextToken:  nextToken,
+		}
+
+		output, err := callAWS(ctx, c, "SelectResourceConfig", region, resourceType, func(ctx context.Context) (*configservice.SelectResourceConfigOutput, middleware.Metadata, error) {
+			output, err := client.SelectResourceConfig(ctx, input)
+			if err != nil {
+				return nil, middleware.Metadata{}, err
+			}
+			return output, output.ResultMetadata, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, result := range output.Results {
+			var row selectedTags
+			if err := json.Unmarshal([]byte(result), &row); err != nil {
+				return fmt.Errorf("invalid tags result: %w", err)
+			}
+			i, ok := byID[row.ResourceID]
+			if !ok || len(row.Tags) == 0 {
+				continue
+			}
+			tags := make(map[string]string, len(row.Tags))
+			for _, tag := range row.Tags {
+				tags[tag.Key] = tag.Value
+			}
+			resources[i].Tags = tags
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+	return nil
 }
@@ -1,17 +1,23 @@
 package awsassetinventory
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 type mockConfigClient struct {
@@ -91,18 +97,20 @@ func TestCollector_Collect_SingleRegion(t *testing.T) {
 			return &configservice.BatchGetResourceConfigOutput{
 				BaseConfigurationItems: []types.BaseConfigurationItem{
 					{
-						ResourceType: "AWS::EC2::Instance",
-						ResourceId:   aws.String("i-12345"),
-						ResourceName: aws.String("instance-1"),
-						AccountId:    aws.String("123456789012"),
-						Arn:          aws.String("arn:aws:ec2:us-east-1:123456789012:instance/i-12345"),
+						ResourceType:     "AWS::EC2::Instance",
+						ResourceId:       aws.String("i-12345"),
+						ResourceName:     aws.String("instance-1"),
+						AccountId:        aws.String("123456789012"),
+						Arn:              aws.String("arn:aws:ec2:us-east-1:123456789012:instance/i-12345"),
+						AvailabilityZone: aws.String("us-east-1a"),
 					},
 					{
-						ResourceType: "AWS::EC2::Instance",
-						ResourceId:   aws.String("i-67890"),
-						ResourceName: aws.String("instance-2"),
-						AccountId:    aws.String("123456789012"),
-						Arn:          aws.String("arn:aws:ec2:us-east-1:123456789012:instance/i-67890"),
+						ResourceType:     "AWS::EC2::Instance",
+						ResourceId:       aws.String("i-67890"),
+						ResourceName:     aws.String("instance-2"),
+						AccountId:        aws.String("123456789012"),
+						Arn:              aws.String("arn:aws:ec2:us-east-1:123456789012:instance/i-67890"),
+						AvailabilityZone: aws.String("Not Applicable"),
 					},
 				},
 			}, nil
@@ -122,6 +130,10 @@ func TestCollector_Collect_SingleRegion(t *testing.T) {
 	if inv.Resources[0].AccountID != "123456789012" {
 		t.Errorf("Collect() resource AccountID = %v, want 123456789012", inv.Resources[0].AccountID)
 	}
+	if inv.Resources[0].AvailabilityZone != "us-east-1a" || inv.Resources[1].AvailabilityZone != "" {
+		t.Errorf("Collect() availability zones = %q, %q, want us-east-1a and none",
+			inv.Resources[0].AvailabilityZone, inv.Resources[1].AvailabilityZone)
+	}
 }
 
 func TestCollector_Collect_MultipleRegions(t *testing.T) {
@@ -298,6 +310,14 @@ func TestCollector_Collect_PartialSuccess(t *testing.T) {
 	if len(inv.Resources) != 1 {
 		t.Errorf("Inventory should have 1 resource from successful region, got %d", len(inv.Resources))
 	}
+
+	want := []RegionStatus{
+		{Region: "us-east-1", Status: RegionCollected, Resources: 1},
+		{Region: "us-west-2", Status: RegionFailed, Error: "access denied"},
+	}
+	if !reflect.DeepEqual(inv.RegionStatus, want) {
+		t.Errorf("RegionStatus = %+v, want %+v", inv.RegionStatus, want)
+	}
 }
 
 func TestCollector_Collect_NilClient(t *testing.T) {
@@ -404,6 +424,100 @@ func TestCollector_Collect_BatchGetFallback(t *testing.T) {
 	if inv.Resources[0].ResourceName != "instance-1" {
 		t.Errorf("Collect() fallback resource name = %v, want instance-1", inv.Resources[0].ResourceName)
 	}
+	if len(inv.Failures) != 1 || inv.Failures[0].ResourceID != "i-12345" || !strings.Contains(inv.Failures[0].Reason, "batch get failed") {
+		t.Errorf("Collect() failures = %+v, want the fallback resource", inv.Failures)
+	}
+}
+
+type mockTagsConfigClient struct {
+	mockConfigClient
+	selectResourceConfigFunc func(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error)
+}
+
+func (m *mockTagsConfigClient) SelectResourceConfig(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error) {
+	return m.selectResourceConfigFunc(ctx, params, optFns...)
+}
+
+func newTagsTestClient(selectFunc func(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error)) *mockTagsConfigClient {
+	return &mockTagsConfigClient{
+		mockConfigClient: mockConfigClient{
+			getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+				return &configservice.GetDiscoveredResourceCountsOutput{
+					ResourceCounts: []types.ResourceCount{{ResourceType: "AWS::EC2::Instance", Count: 2}},
+				}, nil
+			},
+			listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+				return &configservice.ListDiscoveredResourcesOutput{
+					ResourceIdentifiers: []types.ResourceIdentifier{
+						{ResourceId: aws.String("i-12345")},
+						{ResourceId: aws.String("i-67890")},
+					},
+				}, nil
+			},
+			batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+				return &configservice.BatchGetResourceConfigOutput{
+					BaseConfigurationItems: []types.BaseConfigurationItem{
+						{ResourceType: "AWS::EC2::Instance", ResourceId: aws.String("i-12345")},
+						{ResourceType: "AWS::EC2::Instance", ResourceId: aws.String("i-67890")},
+					},
+				}, nil
+			},
+		},
+		selectResourceConfigFunc: selectFunc,
+	}
+}
+
+func TestCollector_Collect_Tags(t *testing.T) {
+	var expressions []string
+	mock := newTagsTestClient(func(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error) {
+		expressions = append(expressions, aws.ToString(params.Expression))
+		if params.NextToken == nil {
+			return &configservice.SelectResourceConfigOutput{
+				Results:   []string{`{"resourceId":"i-12345","tags":[{"key":"env","value":"prod"},{"key":"owner","value":"ops"}]}`},
+				NextToken: aws.String("page-2"),
+			}, nil
+		}
+		return &configservice.SelectResourceConfigOutput{
+			Results: []string{`{"resourceId":"i-67890","tags":[]}`},
+		}, nil
+	})
+
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	inv, err := c.Collect(context.Background(), []Region{"us-east-1"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	if len(expressions) != 2 || expressions[0] != "SELECT resourceId, tags WHERE resourceType = 'AWS::EC2::Instance'" {
+		t.Errorf("SelectResourceConfig expressions = %q, want one query for the type, paged twice", expressions)
+	}
+	want := map[string]string{"env": "prod", "owner": "ops"}
+	if !reflect.DeepEqual(inv.Resources[0].Tags, want) {
+		t.Errorf("i-12345 Tags = %v, want %v", inv.Resources[0].Tags, want)
+	}
+	if inv.Resources[1].Tags != nil {
+		t.Errorf("i-67890 Tags = %v, want none", inv.Resources[1].Tags)
+	}
+}
+
+func TestCollector_Collect_TagsError(t *testing.T) {
+	mock := newTagsTestClient(func(ctx context.Context, params *configservice.SelectResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectResourceConfigOutput, error) {
+		return nil, errors.New("access denied")
+	})
+
+	var buf bytes.Buffer
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	inv, err := c.Collect(context.Background(), []Region{"us-east-1"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v, want tags failures not to fail the region", err)
+	}
+	if len(inv.Resources) != 2 || inv.Incomplete {
+		t.Errorf("Collect() = %d resources, incomplete %v, want 2 complete", len(inv.Resources), inv.Incomplete)
+	}
+	if !strings.Contains(buf.String(), "could not retrieve tags") {
+		t.Errorf("log = %q, want a warning about tags", buf.String())
+	}
 }
 
 func TestCollector_Collect_NoResources(t *testing.T) {
@@ -457,39 +571,99 @@ func TestCollector_Collect_WithLogger(t *testing.T) {
 		},
 	}
 
-	var logs []string
+	var buf bytes.Buffer
 	factory := func(r Region) ConfigClient { return mock }
 	c := NewCollector("test", factory)
-	c.Logger = func(format string, args ...any) {
-		logs = append(logs, fmt.Sprintf(format, args...))
-	}
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
 	_, err := c.Collect(context.Background(), []Region{"us-east-1"})
 	if err != nil {
 		t.Fatalf("Collect() error = %v", err)
 	}
 
-	if len(logs) == 0 {
-		t.Error("Logger should have been called")
+	if buf.Len() == 0 {
+		t.Fatal("Logger should have been called")
 	}
 
-	// Verify expected log messages
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line is not valid JSON: %q", line)
+		}
+		records = append(records, rec)
+	}
+
+	// Verify expected log records and their attributes
 	hasStarting := false
 	hasCompleted := false
-	for _, log := range logs {
-		if strings.Contains(log, "Starting collection") {
-			hasStarting = true
+	hasTypeCollected := false
+	hasCall := false
+	for _, rec := range records {
+		if rec["region"] != "us-east-1" {
+			t.Errorf("log record %v should carry region attribute", rec)
 		}
-		if strings.Contains(log, "Completed with") {
+		switch rec["msg"] {
+		case "starting region collection":
+			hasStarting = true
+		case "region collection completed":
 			hasCompleted = true
+		case "collected resource type":
+			hasTypeCollected = rec["resource_type"] == "AWS::EC2::Instance" && rec["level"] == "DEBUG"
+		case "AWS Config call":
+			hasCall = rec["operation"] != nil
 		}
 	}
 
 	if !hasStarting {
-		t.Error("Logger should have logged 'Starting collection'")
+		t.Error("Logger should have logged 'starting region collection'")
 	}
 	if !hasCompleted {
-		t.Error("Logger should have logged 'Completed with'")
+		t.Error("Logger should have logged 'region collection completed'")
+	}
+	if !hasTypeCollected {
+		t.Error("Logger should have logged 'collected resource type' at debug level with resource_type")
+	}
+	if !hasCall {
+		t.Error("Logger should have logged each AWS Config call with its operation")
+	}
+}
+
+func TestCollector_Collect_LogsThrottleAttempt(t *testing.T) {
+	callCount := 0
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			callCount++
+			if callCount == 1 {
+				return nil, &awshttp.ResponseError{
+					ResponseError: &smithyhttp.ResponseError{
+						Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 400}},
+						Err:      errors.New("ThrottlingException: Rate exceeded"),
+					},
+					RequestID: "req-123",
+				}
+			}
+			return &configservice.GetDiscoveredResourceCountsOutput{}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+	c.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+	if _, err := c.Collect(context.Background(), []Region{"us-east-1"}); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{`msg="AWS Config call throttled"`, "region=us-east-1", "attempt=1", "request_id=req-123"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("log output should contain %s, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "retrying AWS Config call") {
+		t.Error("debug records should not be logged at the default info level")
 	}
 }
 
@@ -575,3 +749,378 @@ func TestCollector_MaxRetriesDefault(t *testing.T) {
 		t.Errorf("maxRetries() = %d, want %d", c.maxRetries(), DefaultMaxRetries)
 	}
 }
+
+type mockAggregateConfigClient struct {
+	mockConfigClient
+	getAggregateDiscoveredResourceCountsFunc func(ctx context.Context, params *configservice.GetAggregateDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetAggregateDiscoveredResourceCountsOutput, error)
+}
+
+func (m *mockAggregateConfigClient) GetAggregateDiscoveredResourceCounts(ctx context.Context, params *configservice.GetAggregateDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetAggregateDiscoveredResourceCountsOutput, error) {
+	if m.getAggregateDiscoveredResourceCountsFunc != nil {
+		return m.getAggregateDiscoveredResourceCountsFunc(ctx, params, optFns...)
+	}
+	return &configservice.GetAggregateDiscoveredResourceCountsOutput{}, nil
+}
+
+func TestCollector_CollectCounts(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{
+					{ResourceType: "AWS::EC2::Instance", Count: 3},
+					{ResourceType: "AWS::S3::Bucket", Count: 2},
+				},
+			}, nil
+		},
+		listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+			t.Error("CollectCounts() should not call ListDiscoveredResources")
+			return &configservice.ListDiscoveredResourcesOutput{}, nil
+		},
+		batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+			t.Error("CollectCounts() should not call BatchGetResourceConfig")
+			return &configservice.BatchGetResourceConfigOutput{}, nil
+		},
+	}
+
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+
+	inv, err := c.CollectCounts(context.Background(), []Region{"us-east-1", "us-west-2"})
+	if err != nil {
+		t.Fatalf("CollectCounts() error = %v", err)
+	}
+	if !inv.CountsOnly {
+		t.Error("CollectCounts() should return a counts-only inventory")
+	}
+	if len(inv.Resources) != 0 {
+		t.Errorf("CollectCounts() resources = %v, want 0", len(inv.Resources))
+	}
+	if len(inv.Counts) != 4 {
+		t.Errorf("CollectCounts() counts = %v, want 4", len(inv.Counts))
+	}
+	if got := inv.ResourceCount(); got != 10 {
+		t.Errorf("CollectCounts() ResourceCount() = %v, want 10", got)
+	}
+	if got := inv.ResourceCountByTypeAndRegion()["us-west-2"]["AWS::EC2::Instance"]; got != 3 {
+		t.Errorf("CollectCounts() us-west-2 EC2 count = %v, want 3", got)
+	}
+	if len(inv.RegionStatus) != 2 || inv.RegionStatus[1].Region != "us-west-2" || inv.RegionStatus[1].Status != RegionCollected {
+		t.Errorf("CollectCounts() RegionStatus = %+v", inv.RegionStatus)
+	}
+}
+
+func TestCollector_CollectCounts_ErrorHandling(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+
+	inv, err := c.CollectCounts(context.Background(), []Region{"us-east-1"})
+	if inv == nil {
+		t.Fatal("CollectCounts() should return partial inventory even on error")
+	}
+
+	var collectErrs CollectErrors
+	if !errors.As(err, &collectErrs) {
+		t.Fatalf("CollectCounts() error = %v, want CollectErrors", err)
+	}
+	if collectErrs.Errors[0].Region != "us-east-1" {
+		t.Errorf("CollectErrors region = %v, want us-east-1", collectErrs.Errors[0].Region)
+	}
+}
+
+func TestCollector_CollectAggregateCounts(t *testing.T) {
+	var mu sync.Mutex
+	var filteredRegions []string
+	mock := &mockAggregateConfigClient{
+		getAggregateDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetAggregateDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetAggregateDiscoveredResourceCountsOutput, error) {
+			if aws.ToString(params.ConfigurationAggregatorName) != "org" {
+				t.Errorf("aggregator name = %v, want org", aws.ToString(params.ConfigurationAggregatorName))
+			}
+			if params.GroupByKey != types.ResourceCountGroupKeyResourceType {
+				t.Errorf("GroupByKey = %v, want RESOURCE_TYPE", params.GroupByKey)
+			}
+			mu.Lock()
+			filteredRegions = append(filteredRegions, aws.ToString(params.Filters.Region))
+			mu.Unlock()
+			return &configservice.GetAggregateDiscoveredResourceCountsOutput{
+				GroupedResourceCounts: []types.GroupedResourceCount{
+					{GroupName: aws.String("AWS::EC2::Instance"), ResourceCount: 7},
+				},
+			}, nil
+		},
+	}
+
+	var requested []Region
+	factory := func(r Region) ConfigClient {
+		requested = append(requested, r)
+		return mock
+	}
+	c := NewCollector("test", factory)
+
+	inv, err := c.CollectAggregateCounts(context.Background(), "org", "us-east-1", []Region{"us-east-1", "eu-west-1"})
+	if err != nil {
+		t.Fatalf("CollectAggregateCounts() error = %v", err)
+	}
+	if len(requested) != 1 || requested[0] != "us-east-1" {
+		t.Errorf("client factory called for %v, want only the aggregator region", requested)
+	}
+	if len(filteredRegions) != 2 {
+		t.Errorf("aggregate queries = %v, want one per region", filteredRegions)
+	}
+	if got := inv.ResourceCountByRegion()["eu-west-1"]; got != 7 {
+		t.Errorf("CollectAggregateCounts() eu-west-1 count = %v, want 7", got)
+	}
+}
+
+func TestCollector_CollectAggregateCounts_Unsupported(t *testing.T) {
+	factory := func(r Region) ConfigClient { return &mockConfigClient{} }
+	c := NewCollector("test", factory)
+
+	inv, err := c.CollectAggregateCounts(context.Background(), "org", "us-west-2", []Region{"us-east-1", "eu-west-1"})
+	if err == nil {
+		t.Fatal("CollectAggregateCounts() expected error for client without aggregator support")
+	}
+	if !inv.Incomplete {
+		t.Error("CollectAggregateCounts() inventory should be incomplete")
+	}
+	var failed []Region
+	for _, s := range inv.FailedRegions() {
+		if s.Error != err.Error() {
+			t.Errorf("region %s error = %q, want %q", s.Region, s.Error, err.Error())
+		}
+		failed = append(failed, s.Region)
+	}
+	if len(failed) != 3 || failed[0] != "eu-west-1" || failed[1] != "us-east-1" || failed[2] != "us-west-2" {
+		t.Errorf("failed regions = %v, want the aggregator region and both requested regions", failed)
+	}
+}
+
+func TestCollector_Collect_RecordsMismatches(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{
+					{ResourceType: "AWS::EC2::Instance", Count: 2},
+				},
+			}, nil
+		},
+		listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+			return &configservice.ListDiscoveredResourcesOutput{
+				ResourceIdentifiers: []types.ResourceIdentifier{
+					{ResourceId: aws.String("i-12345")},
+					{ResourceId: aws.String("i-67890")},
+				},
+			}, nil
+		},
+		batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+			// One key comes back unprocessed and is recorded as a failure.
+			return &configservice.BatchGetResourceConfigOutput{
+				BaseConfigurationItems: []types.BaseConfigurationItem{
+					{ResourceType: "AWS::EC2::Instance", ResourceId: aws.String("i-12345")},
+				},
+				UnprocessedResourceKeys: params.ResourceKeys[1:],
+			}, nil
+		},
+	}
+
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+
+	inv, err := c.Collect(context.Background(), []Region{"us-east-1"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(inv.Counts) != 1 || inv.Counts[0].Count != 2 {
+		t.Errorf("Collect() counts = %+v, want discovered count of 2", inv.Counts)
+	}
+	if len(inv.Mismatches) != 1 {
+		t.Fatalf("Collect() mismatches = %+v, want 1", inv.Mismatches)
+	}
+	want := CountMismatch{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Expected: 2, Collected: 1}
+	if inv.Mismatches[0] != want {
+		t.Errorf("Collect() mismatch = %+v, want %+v", inv.Mismatches[0], want)
+	}
+	if len(inv.Failures) != 1 || inv.Failures[0].ResourceID != "i-67890" || inv.Failures[0].ResourceType != "AWS::EC2::Instance" {
+		t.Errorf("Collect() failures = %+v, want the unprocessed key", inv.Failures)
+	}
+}
+
+func TestCollector_Collect_RegionTimeout(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{
+					{ResourceType: "AWS::EC2::Instance", Count: 2},
+				},
+			}, nil
+		},
+		listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+			if params.NextToken == nil {
+				return &configservice.ListDiscoveredResourcesOutput{
+					ResourceIdentifiers: []types.ResourceIdentifier{
+						{ResourceId: aws.String("i-12345")},
+					},
+					NextToken: aws.String("page2"),
+				}, nil
+			}
+			// Second page hangs until the region deadline expires.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+			return &configservice.BatchGetResourceConfigOutput{
+				BaseConfigurationItems: []types.BaseConfigurationItem{
+					{ResourceType: "AWS::EC2::Instance", ResourceId: aws.String("i-12345")},
+				},
+			}, nil
+		},
+	}
+
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+	c.RegionTimeout = 50 * time.Millisecond
+
+	inv, err := c.Collect(context.Background(), []Region{"us-east-1"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Collect() error = %v, want deadline exceeded", err)
+	}
+	if !inv.Incomplete {
+		t.Error("Collect() should mark inventory incomplete after a region timeout")
+	}
+	if len(inv.Resources) != 1 {
+		t.Errorf("Collect() resources = %v, want 1 collected before the deadline", len(inv.Resources))
+	}
+	if len(inv.Mismatches) != 0 {
+		t.Errorf("Collect() mismatches = %+v, want none for failed regions", inv.Mismatches)
+	}
+}
+
+func TestCollector_Collect_CancelledStopsLaunchingRegions(t *testing.T) {
+	var mu sync.Mutex
+	started := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			mu.Lock()
+			started++
+			mu.Unlock()
+			cancel()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+	c.MaxConcurrency = 1
+
+	regions := []Region{"us-east-1", "us-west-2", "eu-west-1", "ap-southeast-1"}
+	inv, err := c.Collect(ctx, regions)
+
+	var collectErrs CollectErrors
+	if !errors.As(err, &collectErrs) {
+		t.Fatalf("Collect() error = %v, want CollectErrors", err)
+	}
+	if len(collectErrs.Errors) != len(regions) {
+		t.Errorf("CollectErrors should cover all %d regions, got %d", len(regions), len(collectErrs.Errors))
+	}
+	for _, re := range collectErrs.Errors {
+		if !errors.Is(re, context.Canceled) {
+			t.Errorf("region %s error = %v, want context canceled", re.Region, re.Err)
+		}
+	}
+	if started != 1 {
+		t.Errorf("regions started = %d, want 1 (no new regions after cancellation)", started)
+	}
+	if !inv.Incomplete {
+		t.Error("Collect() should mark inventory incomplete when cancelled")
+	}
+}
+
+func TestCollector_Collect_WithObserver(t *testing.T) {
+	var mu sync.Mutex
+	countsCalls := 0
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			mu.Lock()
+			countsCalls++
+			n := countsCalls
+			mu.Unlock()
+			if n == 1 {
+				return nil, errors.New("ThrottlingException: Rate exceeded")
+			}
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{
+					{ResourceType: "AWS::EC2::Instance", Count: 1},
+				},
+			}, nil
+		},
+		listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+			return &configservice.ListDiscoveredResourcesOutput{
+				ResourceIdentifiers: []types.ResourceIdentifier{
+					{ResourceId: aws.String("i-12345")},
+				},
+			}, nil
+		},
+		batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+			return &configservice.BatchGetResourceConfigOutput{
+				BaseConfigurationItems: []types.BaseConfigurationItem{
+					{ResourceType: "AWS::EC2::Instance", ResourceId: aws.String("i-12345")},
+				},
+			}, nil
+		},
+	}
+
+	var events []Event
+	factory := func(r Region) ConfigClient { return mock }
+	c := NewCollector("test", factory)
+	c.Observer = ObserverFunc(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+
+	_, err := c.Collect(context.Background(), []Region{"us-east-1"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	wantKinds := []EventKind{
+		EventRegionStarted,
+		EventThrottled,
+		EventRetry,
+		EventTypesDiscovered,
+		EventTypeCollected,
+		EventRegionCompleted,
+	}
+	if len(events) != len(wantKinds) {
+		t.Fatalf("Observer received %d events, want %d: %+v", len(events), len(wantKinds), events)
+	}
+	for i, kind := range wantKinds {
+		if events[i].Kind != kind {
+			t.Errorf("event[%d].Kind = %s, want %s", i, events[i].Kind, kind)
+		}
+		if events[i].Region != "us-east-1" {
+			t.Errorf("event[%d].Region = %s, want us-east-1", i, events[i].Region)
+		}
+		if events[i].Time.IsZero() {
+			t.Errorf("event[%d].Time should be set", i)
+		}
+	}
+	if events[2].Attempt != 2 {
+		t.Errorf("retry event Attempt = %d, want 2", events[2].Attempt)
+	}
+	if events[4].ResourceType != "AWS::EC2::Instance" || events[4].Count != 1 {
+		t.Errorf("type collected event = %+v, want 1 AWS::EC2::Instance", events[4])
+	}
+	if events[5].Count != 1 || events[5].Err != nil {
+		t.Errorf("region completed event = %+v, want 1 resource and no error", events[5])
+	}
+}
@@ -0,0 +1,185 @@
+package awsassetinventory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCollectionRunning is returned by Daemon.RunOnce when the previous run
+// has not finished yet.
+var ErrCollectionRunning = errors.New("a collection is already running")
+
+// ErrNoInventory is returned by a Daemon's InventorySource before its first
+// run has finished.
+var ErrNoInventory = errors.New("no inventory collected yet")
+
+// Daemon runs repeated collections for a long-running process. It keeps the
+// latest inventory in memory and writes each one to Snapshots, if set.
+// Runs never overlap: a run requested while another is in progress is
+// skipped.
+type Daemon struct {
+	Collector  *Collector
+	Regions    []Region
+	Snapshots  *SnapshotDir
+	Logger     *slog.Logger
+	RunTimeout time.Duration // 0 means no deadline per run
+
+	running sync.Mutex
+	mu      sync.RWMutex
+	latest  *Inventory
+	outcome *CollectionOutcome
+}
+
+// NewDaemon creates a Daemon that collects regions with collector and stores
+// snapshots in snapshots, which may be nil.
+func NewDaemon(collector *Collector, regions []Region, snapshots *SnapshotDir) *Daemon {
+	return &Daemon{
+		Collector: collector,
+		Regions:   regions,
+		Snapshots: snapshots,
+	}
+}
+
+func (d *Daemon) logger() *slog.Logger {
+	if d.Logger != nil {
+		return d.Logger
+	}
+	return discardLogger
+}
+
+// Latest returns the inventory from the most recent run, or nil before the
+// first run finishes. Inventories from runs that failed part-way are kept
+// and marked Incomplete.
+func (d *Daemon) Latest() *Inventory {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.latest
+}
+
+// Source returns an InventorySource that serves the latest inventory. It
+// returns ErrNoInventory until the first run finishes.
+func (d *Daemon) Source() InventorySource {
+	return func() (*Inventory, error) {
+		if inv := d.Latest(); inv != nil {
+			return inv, nil
+		}
+		return nil, ErrNoInventory
+	}
+}
+
+// Outcome returns a summary of the daemon's collection runs, or nil before
+// the first run finishes.
+func (d *Daemon) Outcome() *CollectionOutcome {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.outcome == nil {
+		return nil
+	}
+	o := *d.outcome
+	return &o
+}
+
+// recordOutcome updates the run summary with a run that started at start.
+func (d *Daemon) recordOutcome(start time.Time, inv *Inventory, rec *outcomeRecorder, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	o := &CollectionOutcome{}
+	if d.outcome != nil {
+		o.LastSuccess = d.outcome.LastSuccess
+		o.Runs = d.outcome.Runs
+		o.FailedRuns = d.outcome.FailedRuns
+	}
+
+	o.StartedAt = start
+	o.Duration = time.Since(start)
+	o.Success = err == nil
+	o.Throttles = rec.throttles
+	if inv != nil {
+		o.Resources = inv.ResourceCount()
+		// Regions that never started, because the run timed out or was
+		// cancelled, emit no events but are recorded as failed.
+		for _, s := range inv.FailedRegions() {
+			o.FailedRegions = append(o.FailedRegions, s.Region)
+		}
+	}
+	o.Runs++
+	if o.Success {
+		o.LastSuccess = start.Add(o.Duration)
+	} else {
+		o.FailedRuns++
+	}
+	d.outcome = o
+}
+
+// RunOnce performs one collection, stores the result as the latest
+// inventory, writes and prunes snapshots, and logs the outcome. It returns
+// ErrCollectionRunning without collecting if another run is in progress.
+func (d *Daemon) RunOnce(ctx context.Context) (*Inventory, error) {
+	log := d.logger()
+	if !d.running.TryLock() {
+		log.Warn("skipping collection; previous run still in progress")
+		return nil, ErrCollectionRunning
+	}
+	defer d.running.Unlock()
+
+	if d.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.RunTimeout)
+		defer cancel()
+	}
+
+	// Count throttles for Outcome, passing events on to
+	// the Collector's own Observer. The run uses a copy of the Collector so
+	// that the caller's is never modified.
+	rec := &outcomeRecorder{next: d.Collector.Observer}
+	collector := d.Collector.withObserver(rec)
+
+	start := time.Now()
+	log.Info("collection started", "regions", len(d.Regions))
+
+	inv, err := collector.Collect(ctx, d.Regions)
+	d.recordOutcome(start, inv, rec, err)
+	if inv == nil {
+		log.Error("collection failed", "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.latest = inv
+	d.mu.Unlock()
+
+	attrs := []any{"resources", inv.ResourceCount(), "duration", time.Since(start).Round(time.Millisecond)}
+	if err != nil {
+		log.Error("collection finished with errors", append(attrs, "incomplete", true, "error", err)...)
+	} else {
+		log.Info("collection finished", attrs...)
+	}
+
+	if d.Snapshots == nil {
+		return inv, err
+	}
+
+	path, writeErr := d.Snapshots.Write(inv)
+	if writeErr != nil {
+		log.Error("failed to write snapshot", "error", writeErr)
+		return inv, errors.Join(err, writeErr)
+	}
+	log.Info("snapshot written", "path", path)
+
+	removed, pruneErr := d.Snapshots.Prune()
+	if len(removed) > 0 {
+		log.Info("pruned old snapshots", "removed", len(removed), "retain", d.Snapshots.Retain)
+	}
+	if pruneErr != nil {
+		log.Error("failed to prune snapshots", "error", pruneErr)
+		return inv, errors.Join(err, pruneErr)
+	}
+
+	return inv, err
+}
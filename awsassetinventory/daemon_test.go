@@ -0,0 +1,199 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+)
+
+func TestDaemon_RunOnce(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return &configservice.GetDiscoveredResourceCountsOutput{}, nil
+		},
+	}
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	snapshots := NewSnapshotDir(t.TempDir(), 1)
+	d := NewDaemon(c, []Region{"us-east-1"}, snapshots)
+
+	if d.Latest() != nil {
+		t.Error("Latest() before the first run should be nil")
+	}
+
+	inv, err := d.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if d.Latest() != inv {
+		t.Error("Latest() should return the inventory from the last run")
+	}
+
+	paths, err := snapshots.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("snapshots after RunOnce() = %v, want 1", paths)
+	}
+}
+
+func TestDaemon_RunOnce_KeepsIncompleteInventory(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	d := NewDaemon(c, []Region{"us-east-1"}, nil)
+
+	inv, err := d.RunOnce(context.Background())
+	if err == nil {
+		t.Fatal("RunOnce() should return the collection error")
+	}
+	if inv == nil || !inv.Incomplete || d.Latest() != inv {
+		t.Error("RunOnce() should keep the incomplete inventory as the latest")
+	}
+}
+
+func TestDaemon_RunOnce_SkipsOverlappingRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			close(started)
+			<-release
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{},
+			}, nil
+		},
+	}
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	d := NewDaemon(c, []Region{"us-east-1"}, nil)
+
+	done := make(chan error)
+	go func() {
+		_, err := d.RunOnce(context.Background())
+		done <- err
+	}()
+	<-started
+
+	if _, err := d.RunOnce(context.Background()); !errors.Is(err, ErrCollectionRunning) {
+		t.Errorf("overlapping RunOnce() error = %v, want ErrCollectionRunning", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first RunOnce() error = %v", err)
+	}
+}
+
+func TestDaemon_Outcome(t *testing.T) {
+	fail := true
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			if fail {
+				return nil, errors.New("access denied")
+			}
+			return &configservice.GetDiscoveredResourceCountsOutput{}, nil
+		},
+	}
+	var observed int
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.Observer = ObserverFunc(func(Event) { observed++ })
+	d := NewDaemon(c, []Region{"us-east-1"}, nil)
+
+	if d.Outcome() != nil {
+		t.Error("Outcome() before the first run should be nil")
+	}
+	if _, err := d.Source()(); !errors.Is(err, ErrNoInventory) {
+		t.Errorf("Source() before the first run error = %v, want ErrNoInventory", err)
+	}
+
+	_, _ = d.RunOnce(context.Background())
+	o := d.Outcome()
+	if o.Success || o.Runs != 1 || o.FailedRuns != 1 || len(o.FailedRegions) != 1 || !o.LastSuccess.IsZero() {
+		t.Errorf("Outcome() after failed run = %+v", o)
+	}
+	if observed == 0 {
+		t.Error("the Collector's own Observer should still receive events")
+	}
+
+	fail = false
+	_, _ = d.RunOnce(context.Background())
+	o = d.Outcome()
+	if !o.Success || o.Runs != 2 || o.FailedRuns != 1 || len(o.FailedRegions) != 0 || o.LastSuccess.IsZero() {
+		t.Errorf("Outcome() after successful run = %+v", o)
+	}
+	if _, ok := c.Observer.(ObserverFunc); !ok {
+		t.Error("RunOnce() should restore the Collector's Observer")
+	}
+}
+
+func TestDaemon_RunOnce_ManyRegions(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+	var observed atomic.Int64
+	observer := ObserverFunc(func(Event) { observed.Add(1) })
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.Observer = observer
+	regions := []Region{
+		"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+		"eu-west-1", "eu-west-2", "eu-central-1", "ap-southeast-2",
+	}
+	d := NewDaemon(c, regions, nil)
+
+	// Regions are collected in parallel, so run with -race to check that
+	// their events are recorded safely.
+	_, _ = d.RunOnce(context.Background())
+
+	o := d.Outcome()
+	if len(o.FailedRegions) != len(regions) {
+		t.Errorf("Outcome().FailedRegions = %v, want all %d regions", o.FailedRegions, len(regions))
+	}
+	if observed.Load() == 0 {
+		t.Error("the Collector's own Observer should still receive events")
+	}
+	if _, ok := c.Observer.(ObserverFunc); !ok {
+		t.Error("RunOnce() should leave the Collector's Observer unchanged")
+	}
+}
+
+func TestDaemon_RunOnce_TimeoutWithQueuedRegions(t *testing.T) {
+	mock := &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.MaxConcurrency = 1
+	regions := []Region{"us-east-1", "us-east-2", "us-west-1"}
+	d := NewDaemon(c, regions, nil)
+	d.RunTimeout = 20 * time.Millisecond
+
+	// Only the first region gets a slot before the deadline; the others
+	// never start and emit no events.
+	inv, _ := d.RunOnce(context.Background())
+
+	o := d.Outcome()
+	if len(o.FailedRegions) != len(regions) || len(inv.FailedRegions()) != len(regions) {
+		t.Errorf("Outcome().FailedRegions = %v, want all %d regions", o.FailedRegions, len(regions))
+	}
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, nil, o); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "aws_asset_inventory_collection_region_failures 3") {
+		t.Errorf("metrics = %s, want 3 region failures", buf.String())
+	}
+}
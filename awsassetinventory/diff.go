@@ -0,0 +1,180 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ErrCountsOnlyDiff is returned by DiffInventories when either inventory is
+// counts-only and so has no resources to compare.
+var ErrCountsOnlyDiff = errors.New("cannot diff counts-only inventories")
+
+// DiffSource identifies one side of an InventoryDiff.
+type DiffSource struct {
+	CollectedAt time.Time `json:"collectedAt"`
+	Profile     string    `json:"profile"`
+	Regions     []Region  `json:"regions"`
+}
+
+// ResourceChange is a resource present in both inventories whose
+// configuration differs.
+type ResourceChange struct {
+	Key  string   `json:"key"`
+	From Resource `json:"from"`
+	To   Resource `json:"to"`
+}
+
+// DiffCounts is the number of resources added, removed and changed.
+type DiffCounts struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// InventoryDiff describes how one inventory differs from an earlier one.
+// Resources are matched by ARN, or by type, region and ID when either copy
+// has no ARN, as when configuration could not be retrieved for it. Added,
+// Removed and Changed are sorted by resource type, region and ID.
+type InventoryDiff struct {
+	From     DiffSource                  `json:"from"`
+	To       DiffSource                  `json:"to"`
+	Added    []Resource                  `json:"added"`
+	Removed  []Resource                  `json:"removed"`
+	Changed  []ResourceChange            `json:"changed"`
+	ByType   map[ResourceType]DiffCounts `json:"byType"`
+	ByRegion map[Region]DiffCounts       `json:"byRegion"`
+}
+
+// DiffInventories compares from with the later inventory to. A resource is
+// changed when its Configuration differs, ignoring JSON formatting and key
+// order.
+func DiffInventories(from, to *Inventory) (*InventoryDiff, error) {
+	if from.CountsOnly || to.CountsOnly {
+		return nil, ErrCountsOnlyDiff
+	}
+
+	d := &InventoryDiff{
+		From:     DiffSource{CollectedAt: from.CollectedAt, Profile: from.Profile, Regions: from.Regions},
+		To:       DiffSource{CollectedAt: to.CollectedAt, Profile: to.Profile, Regions: to.Regions},
+		Added:    make([]Resource, 0),
+		Removed:  make([]Resource, 0),
+		Changed:  make([]ResourceChange, 0),
+		ByType:   make(map[ResourceType]DiffCounts),
+		ByRegion: make(map[Region]DiffCounts),
+	}
+
+	m := newResourceMatcher(from.Resources)
+	for _, r := range to.Resources {
+		old, ok := m.match(r)
+		switch {
+		case !ok:
+			d.Added = append(d.Added, r)
+			d.count(r, func(c *DiffCounts) { c.Added++ })
+		case !configEqual(old.Configuration, r.Configuration):
+			d.Changed = append(d.Changed, ResourceChange{Key: r.Key(), From: old, To: r})
+			d.count(r, func(c *DiffCounts) { c.Changed++ })
+		}
+	}
+
+	for _, r := range m.unmatched() {
+		d.Removed = append(d.Removed, r)
+		d.count(r, func(c *DiffCounts) { c.Removed++ })
+	}
+
+	SortByTypeRegionID(d.Added)
+	SortByTypeRegionID(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool {
+		return lessByTypeRegionID(d.Changed[i].To, d.Changed[j].To)
+	})
+	return d, nil
+}
+
+// resourceMatcher pairs resources with those of an earlier inventory. Each
+// earlier resource is matched at most once.
+type resourceMatcher struct {
+	resources []Resource
+	matched   []bool
+	byARN     map[string]int
+	byID      map[string][]int
+}
+
+func newResourceMatcher(resources []Resource) *resourceMatcher {
+	m := &resourceMatcher{
+		resources: resources,
+		matched:   make([]bool, len(resources)),
+		byARN:     make(map[string]int),
+		byID:      make(map[string][]int),
+	}
+	for i, r := range resources {
+		if r.ARN != "" {
+			m.byARN[r.ARN] = i
+		}
+		m.byID[r.idKey()] = append(m.byID[r.idKey()], i)
+	}
+	return m
+}
+
+// match returns the earlier copy of r: the one with its ARN, or else one
+// with its type, region and ID where either copy has no ARN.
+func (m *resourceMatcher) match(r Resource) (Resource, bool) {
+	if i, ok := m.byARN[r.ARN]; ok && r.ARN != "" && !m.matched[i] {
+		m.matched[i] = true
+		return m.resources[i], true
+	}
+	for _, i := range m.byID[r.idKey()] {
+		if !m.matched[i] && (r.ARN == "" || m.resources[i].ARN == "") {
+			m.matched[i] = true
+			return m.resources[i], true
+		}
+	}
+	return Resource{}, false
+}
+
+// unmatched returns the earlier resources that match returned for none.
+func (m *resourceMatcher) unmatched() []Resource {
+	var rest []Resource
+	for i, r := range m.resources {
+		if !m.matched[i] {
+			rest = append(rest, r)
+		}
+	}
+	return rest
+}
+
+func (d *InventoryDiff) count(r Resource, inc func(*DiffCounts)) {
+	c := d.ByType[r.ResourceType]
+	inc(&c)
+	d.ByType[r.ResourceType] = c
+
+	c = d.ByRegion[r.Region]
+	inc(&c)
+	d.ByRegion[r.Region] = c
+}
+
+// Empty reports whether the inventories have the same resources and
+// configurations.
+func (d *InventoryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ToJSON serializes the diff to JSON.
+func (d *InventoryDiff) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// configEqual reports whether two configuration documents are equivalent.
+// Documents that are not valid JSON are compared byte for byte.
+func configEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
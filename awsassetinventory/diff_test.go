@@ -0,0 +1,210 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDiffInventories() (*Inventory, *Inventory) {
+	from := NewInventory("prod", []Region{"us-east-1"})
+	from.CollectedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	from.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", Configuration: json.RawMessage(`{"versioning":"Enabled","name":"logs"}`)})
+	from.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "old", Region: "us-east-1",
+		ARN: "arn:aws:s3:::old"})
+	from.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", Configuration: json.RawMessage(`{"instanceType":"t3.micro"}`)})
+	from.AddResource(Resource{ResourceType: "AWS::Config::ResourceCompliance", ResourceID: "c-1", Region: "us-east-1"})
+
+	to := NewInventory("prod", []Region{"us-east-1", "us-west-2"})
+	to.CollectedAt = time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	// Same configuration, different formatting and key order.
+	to.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", Configuration: json.RawMessage(`{ "name": "logs", "versioning": "Enabled" }`)})
+	to.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", Configuration: json.RawMessage(`{"instanceType":"m5.large"}`)})
+	to.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2",
+		ARN: "arn:aws:ec2:us-west-2:123456789012:instance/i-2"})
+	to.AddResource(Resource{ResourceType: "AWS::Config::ResourceCompliance", ResourceID: "c-1", Region: "us-east-1"})
+	return from, to
+}
+
+func TestResource_Key(t *testing.T) {
+	withARN := Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1", ARN: "arn:aws:s3:::logs"}
+	if got := withARN.Key(); got != "arn:aws:s3:::logs" {
+		t.Errorf("Key() = %s, want the ARN", got)
+	}
+	withoutARN := Resource{ResourceType: "AWS::Config::ResourceCompliance", ResourceID: "c-1", Region: "us-east-1"}
+	if got := withoutARN.Key(); got != "AWS::Config::ResourceCompliance/us-east-1/c-1" {
+		t.Errorf("Key() = %s, want type/region/id", got)
+	}
+}
+
+func TestDiffInventories(t *testing.T) {
+	from, to := newDiffInventories()
+	d, err := DiffInventories(from, to)
+	if err != nil {
+		t.Fatalf("DiffInventories() error = %v", err)
+	}
+
+	if len(d.Added) != 1 || d.Added[0].ResourceID != "i-2" {
+		t.Errorf("Added = %v, want i-2", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ResourceID != "old" {
+		t.Errorf("Removed = %v, want old", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Key != "arn:aws:ec2:us-east-1:123456789012:instance/i-1" {
+		t.Fatalf("Changed = %v, want i-1", d.Changed)
+	}
+	if string(d.Changed[0].From.Configuration) != `{"instanceType":"t3.micro"}` {
+		t.Errorf("Changed[0].From = %s", d.Changed[0].From.Configuration)
+	}
+
+	if got := d.ByType["AWS::EC2::Instance"]; got != (DiffCounts{Added: 1, Changed: 1}) {
+		t.Errorf("ByType[EC2] = %+v", got)
+	}
+	if got := d.ByType["AWS::S3::Bucket"]; got != (DiffCounts{Removed: 1}) {
+		t.Errorf("ByType[S3] = %+v", got)
+	}
+	if got := d.ByRegion["us-west-2"]; got != (DiffCounts{Added: 1}) {
+		t.Errorf("ByRegion[us-west-2] = %+v", got)
+	}
+	if d.Empty() {
+		t.Error("Empty() = true, want false")
+	}
+}
+
+func TestDiffInventories_MatchesWithoutARN(t *testing.T) {
+	withARN := Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", Configuration: json.RawMessage(`{"instanceType":"t3.micro"}`)}
+	// Kept with only its listed details when its configuration could not
+	// be retrieved.
+	withoutARN := Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1"}
+	// Same type, region and ID, but a different resource.
+	otherAccount := Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:210987654321:instance/i-1"}
+
+	from := NewInventory("prod", []Region{"us-east-1"})
+	from.AddResource(withARN)
+	to := NewInventory("prod", []Region{"us-east-1"})
+	to.AddResource(withoutARN)
+
+	for _, tt := range []struct {
+		name     string
+		from, to *Inventory
+	}{
+		{"ARN lost", from, to},
+		{"ARN gained", to, from},
+	} {
+		d, err := DiffInventories(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(d.Added) != 0 || len(d.Removed) != 0 || len(d.Changed) != 1 {
+			t.Errorf("%s: added %d, removed %d, changed %d, want the resource changed only",
+				tt.name, len(d.Added), len(d.Removed), len(d.Changed))
+		}
+	}
+
+	other := NewInventory("prod", []Region{"us-east-1"})
+	other.AddResource(otherAccount)
+	d, err := DiffInventories(from, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Added) != 1 || len(d.Removed) != 1 || len(d.Changed) != 0 {
+		t.Errorf("different ARNs: added %d, removed %d, changed %d, want one added and one removed",
+			len(d.Added), len(d.Removed), len(d.Changed))
+	}
+}
+
+func TestDiffInventories_Identical(t *testing.T) {
+	from, _ := newDiffInventories()
+	d, err := DiffInventories(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Empty() {
+		t.Errorf("diff of an inventory with itself should be empty, got %+v", d)
+	}
+}
+
+func TestDiffInventories_CountsOnly(t *testing.T) {
+	from, to := newDiffInventories()
+	to.CountsOnly = true
+	if _, err := DiffInventories(from, to); !errors.Is(err, ErrCountsOnlyDiff) {
+		t.Errorf("DiffInventories() error = %v, want ErrCountsOnlyDiff", err)
+	}
+}
+
+func TestInventoryDiff_ToJSON(t *testing.T) {
+	from, to := newDiffInventories()
+	d, err := DiffInventories(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := d.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	var decoded InventoryDiff
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("ToJSON() output is not valid JSON: %v", err)
+	}
+	if len(decoded.Added) != 1 || decoded.ByType["AWS::EC2::Instance"].Changed != 1 {
+		t.Errorf("decoded diff = %+v", decoded)
+	}
+}
+
+func TestDiffReportGenerator_Generate(t *testing.T) {
+	from, to := newDiffInventories()
+	d, err := DiffInventories(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewDiffReportGenerator(d).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"# AWS Asset Inventory Diff",
+		"**From:** 2024-01-15 10:00:00 UTC (prod)",
+		"**Added:** 1\n**Removed:** 1\n**Changed:** 1",
+		"## By Resource Type",
+		"| AWS::EC2::Instance | 1 | 0 | 1 |",
+		"## By Region",
+		"| us-west-2 | 1 | 0 | 0 |",
+		"## Added Resources (1)",
+		"## Removed Resources (1)",
+		"| AWS::S3::Bucket | - | old | us-east-1 | arn:aws:s3:::old |",
+		"## Changed Resources (1)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("diff report should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestDiffReportGenerator_NoDifferences(t *testing.T) {
+	from, _ := newDiffInventories()
+	d, err := DiffInventories(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewDiffReportGenerator(d).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No differences found.") || strings.Contains(buf.String(), "## By Region") {
+		t.Errorf("empty diff report = %s", buf.String())
+	}
+}
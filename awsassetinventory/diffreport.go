@@ -0,0 +1,180 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DiffReportGenerator generates markdown reports from an InventoryDiff, in
+// the same layout as ReportGenerator.
+type DiffReportGenerator struct {
+	diff *InventoryDiff
+}
+
+// NewDiffReportGenerator creates a new DiffReportGenerator for the given diff.
+func NewDiffReportGenerator(d *InventoryDiff) *DiffReportGenerator {
+	return &DiffReportGenerator{diff: d}
+}
+
+// Generate writes a complete markdown diff report to the provided writer.
+func (dg *DiffReportGenerator) Generate(w io.Writer) error {
+	if err := dg.writeHeader(w); err != nil {
+		return err
+	}
+	if dg.diff.Empty() {
+		_, err := fmt.Fprintf(w, "No differences found.\n")
+		return err
+	}
+	if err := dg.writeByType(w); err != nil {
+		return err
+	}
+	if err := dg.writeByRegion(w); err != nil {
+		return err
+	}
+	if err := dg.writeResources(w, "Added Resources", dg.diff.Added); err != nil {
+		return err
+	}
+	if err := dg.writeResources(w, "Removed Resources", dg.diff.Removed); err != nil {
+		return err
+	}
+	changed := make([]Resource, len(dg.diff.Changed))
+	for i, c := range dg.diff.Changed {
+		changed[i] = c.To
+	}
+	return dg.writeResources(w, "Changed Resources", changed)
+}
+
+func (dg *DiffReportGenerator) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# AWS Asset Inventory Diff\n\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**From:** %s (%s)\n", dg.diff.From.CollectedAt.Format("2006-01-02 15:04:05 UTC"), dg.diff.From.Profile)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**To:** %s (%s)\n", dg.diff.To.CollectedAt.Format("2006-01-02 15:04:05 UTC"), dg.diff.To.Profile)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Added:** %d\n", len(dg.diff.Added))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Removed:** %d\n", len(dg.diff.Removed))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Changed:** %d\n\n", len(dg.diff.Changed))
+	return err
+}
+
+func (dg *DiffReportGenerator) writeByType(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## By Resource Type\n\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "| Resource Type | Added | Removed | Changed |\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|---------------|-------|---------|---------|\n")
+	if err != nil {
+		return err
+	}
+
+	types := make([]ResourceType, 0, len(dg.diff.ByType))
+	for rt := range dg.diff.ByType {
+		types = append(types, rt)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, rt := range types {
+		c := dg.diff.ByType[rt]
+		_, err = fmt.Fprintf(w, "| %s | %d | %d | %d |\n", rt, c.Added, c.Removed, c.Changed)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
+func (dg *DiffReportGenerator) writeByRegion(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## By Region\n\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "| Region | Added | Removed | Changed |\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|--------|-------|---------|---------|\n")
+	if err != nil {
+		return err
+	}
+
+	regions := make([]Region, 0, len(dg.diff.ByRegion))
+	for r := range dg.diff.ByRegion {
+		regions = append(regions, r)
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i] < regions[j] })
+
+	for _, r := range regions {
+		c := dg.diff.ByRegion[r]
+		_, err = fmt.Fprintf(w, "| %s | %d | %d | %d |\n", r, c.Added, c.Removed, c.Changed)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
+func (dg *DiffReportGenerator) writeResources(w io.Writer, title string, resources []Resource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, "## %s (%d)\n\n", title, len(resources))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "| Resource Type | Name | ID | Region | ARN |\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|---------------|------|----|--------|-----|\n")
+	if err != nil {
+		return err
+	}
+
+	for _, r := range resources {
+		name := r.ResourceName
+		if name == "" {
+			name = "-"
+		}
+		arn := r.ARN
+		if arn == "" {
+			arn = "-"
+		}
+		_, err = fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			r.ResourceType,
+			escapeMarkdown(name),
+			escapeMarkdown(r.ResourceID),
+			r.Region,
+			escapeMarkdown(truncateARN(arn)))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
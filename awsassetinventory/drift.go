@@ -0,0 +1,188 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AllResourceTypes is the DriftRules key whose ignore patterns apply to
+// every resource type.
+const AllResourceTypes = "*"
+
+// DriftRules lists configuration fields to leave out of drift, keyed by
+// resource type or AllResourceTypes. Each pattern is a JSON pointer whose
+// tokens may be "*" to match any single key or index. A pattern also
+// ignores everything beneath the location it matches. The empty pointer,
+// which would match the whole configuration and hide all drift, matches
+// nothing and is rejected by LoadDriftRules.
+//
+// Rules files use the same shape:
+//
+//	{
+//	  "ignore": {
+//	    "*": ["/tags"],
+//	    "AWS::EC2::Instance": ["/launchTime", "/networkInterfaces/*/attachment/attachTime"]
+//	  }
+//	}
+type DriftRules struct {
+	Ignore map[string][]string `json:"ignore"`
+}
+
+// DefaultDriftRules returns rules that ignore the attachment and launch
+// timestamps AWS updates in EC2 configurations without a configuration
+// change. They need no rules for configurationStateId and the other
+// configuration item fields AWS Config changes on every recording, because
+// those are not part of a Resource's Configuration.
+func DefaultDriftRules() *DriftRules {
+	return &DriftRules{Ignore: map[string][]string{
+		"AWS::EC2::Instance": {
+			"/launchTime",
+			"/networkInterfaces/*/attachment/attachTime",
+			"/blockDeviceMappings/*/ebs/attachTime",
+		},
+		"AWS::EC2::Volume": {
+			"/attachments/*/attachTime",
+		},
+	}}
+}
+
+// LoadDriftRules reads drift rules from a JSON file.
+func LoadDriftRules(path string) (*DriftRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules DriftRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for rt, patterns := range rules.Ignore {
+		for _, p := range patterns {
+			if !strings.HasPrefix(p, "/") {
+				return nil, fmt.Errorf("%s: invalid ignore pattern %q for %s: must be a JSON pointer starting with /", path, p, rt)
+			}
+		}
+	}
+	return &rules, nil
+}
+
+// Merge returns rules that ignore everything r or other ignores. Either
+// may be nil.
+func (r *DriftRules) Merge(other *DriftRules) *DriftRules {
+	merged := &DriftRules{Ignore: make(map[string][]string)}
+	for _, rules := range []*DriftRules{r, other} {
+		if rules == nil {
+			continue
+		}
+		for rt, patterns := range rules.Ignore {
+			merged.Ignore[rt] = append(merged.Ignore[rt], patterns...)
+		}
+	}
+	return merged
+}
+
+// Ignores reports whether a change at path in a resourceType configuration
+// is ignored.
+func (r *DriftRules) Ignores(resourceType ResourceType, path string) bool {
+	if r == nil {
+		return false
+	}
+	for _, key := range []string{AllResourceTypes, resourceType.String()} {
+		for _, pattern := range r.Ignore[key] {
+			if pointerPatternMatches(pattern, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pointerPatternMatches reports whether pattern matches path or one of its
+// ancestors.
+func pointerPatternMatches(pattern, path string) bool {
+	if pattern == "" {
+		return false
+	}
+	pt := strings.Split(pattern, "/")
+	at := strings.Split(path, "/")
+	if len(pt) > len(at) {
+		return false
+	}
+	for i, token := range pt {
+		if token != "*" && token != at[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ResourceDrift is the field-level change log of one resource whose
+// configuration changed.
+type ResourceDrift struct {
+	Key          string        `json:"key"`
+	ResourceType ResourceType  `json:"resourceType"`
+	ResourceID   string        `json:"resourceId"`
+	ResourceName string        `json:"resourceName,omitempty"`
+	Region       Region        `json:"awsRegion"`
+	ARN          string        `json:"arn,omitempty"`
+	Changes      []FieldChange `json:"changes"`
+}
+
+// DriftReport lists configuration drift between two inventories.
+// Resources are in the order of InventoryDiff.Changed.
+type DriftReport struct {
+	From      DiffSource      `json:"from"`
+	To        DiffSource      `json:"to"`
+	Resources []ResourceDrift `json:"resources"`
+}
+
+// Drift computes the field-level configuration changes of every changed
+// resource in the diff, leaving out fields ignored by rules, which may be
+// nil. Resources whose changes are all ignored are omitted.
+func (d *InventoryDiff) Drift(rules *DriftRules) (*DriftReport, error) {
+	report := &DriftReport{From: d.From, To: d.To, Resources: make([]ResourceDrift, 0)}
+
+	for _, c := range d.Changed {
+		changes, err := DiffJSON(c.From.Configuration, c.To.Configuration)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Key, err)
+		}
+
+		kept := changes[:0]
+		for _, fc := range changes {
+			if !rules.Ignores(c.To.ResourceType, fc.Path) {
+				kept = append(kept, fc)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+
+		report.Resources = append(report.Resources, ResourceDrift{
+			Key:          c.Key,
+			ResourceType: c.To.ResourceType,
+			ResourceID:   c.To.ResourceID,
+			ResourceName: c.To.ResourceName,
+			Region:       c.To.Region,
+			ARN:          c.To.ARN,
+			Changes:      kept,
+		})
+	}
+	return report, nil
+}
+
+// FieldChangeCount returns the total number of field changes.
+func (r *DriftReport) FieldChangeCount() int {
+	n := 0
+	for _, rd := range r.Resources {
+		n += len(rd.Changes)
+	}
+	return n
+}
+
+// ToJSON serializes the drift report to JSON.
+func (r *DriftReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
@@ -0,0 +1,207 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// driftTestInstance returns the configuration AWS Config records for an EC2
+// instance, which is the instance as DescribeInstances returns it.
+func driftTestInstance(id, instanceType, launchTime, nicAttachTime, nicStatus, ebsAttachTime string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+		"amiLaunchIndex": 0,
+		"imageId": "ami-0abc1234",
+		"instanceId": %q,
+		"instanceType": %q,
+		"launchTime": %q,
+		"placement": {"availabilityZone": "us-east-1a", "tenancy": "default"},
+		"state": {"code": 16, "name": "running"},
+		"blockDeviceMappings": [
+			{"deviceName": "/dev/xvda", "ebs": {"attachTime": %q, "deleteOnTermination": true, "status": "attached", "volumeId": "vol-1"}}
+		],
+		"networkInterfaces": [
+			{"attachment": {"attachTime": %q, "attachmentId": "eni-attach-1", "deleteOnTermination": true, "deviceIndex": 0, "status": %q}, "networkInterfaceId": "eni-1"}
+		],
+		"tags": [{"key": "Name", "value": "web"}]
+	}`, id, instanceType, launchTime, ebsAttachTime, nicAttachTime, nicStatus))
+}
+
+func newDriftDiff(t *testing.T) *InventoryDiff {
+	t.Helper()
+	from := NewInventory("prod", []Region{"us-east-1"})
+	from.CollectedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	from.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1",
+		Configuration: driftTestInstance("i-1", "t3.micro", "2024-01-01T09:00:00.000Z",
+			"2024-01-01T09:00:00.000Z", "attached", "2024-01-01T09:00:01.000Z")})
+	from.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-east-1",
+		Configuration: driftTestInstance("i-2", "t3.micro", "2024-01-01T09:00:00.000Z",
+			"2024-01-01T09:00:00.000Z", "attached", "2024-01-01T09:00:01.000Z")})
+
+	to := NewInventory("prod", []Region{"us-east-1"})
+	to.CollectedAt = time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	to.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1",
+		ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1",
+		Configuration: driftTestInstance("i-1", "m5.large", "2024-01-02T09:00:00.000Z",
+			"2024-01-02T09:00:00.000Z", "detached", "2024-01-01T09:00:01.000Z")})
+	to.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-east-1",
+		Configuration: driftTestInstance("i-2", "t3.micro", "2024-01-01T09:00:00.000Z",
+			"2024-01-01T09:00:00.000Z", "attached", "2024-01-02T09:00:01.000Z")})
+
+	d, err := DiffInventories(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDriftRules_Ignores(t *testing.T) {
+	rules := &DriftRules{Ignore: map[string][]string{
+		AllResourceTypes:     {"/configurationStateId"},
+		"AWS::EC2::Instance": {"/networkInterfaces/*/attachment/attachTime", "/metadata"},
+	}}
+
+	tests := []struct {
+		resourceType ResourceType
+		path         string
+		want         bool
+	}{
+		{"AWS::S3::Bucket", "/configurationStateId", true},
+		{"AWS::EC2::Instance", "/networkInterfaces/0/attachment/attachTime", true},
+		{"AWS::EC2::Instance", "/networkInterfaces/0/attachment/status", false},
+		{"AWS::EC2::Instance", "/metadata/generation", true},
+		{"AWS::EC2::Instance", "/metadataOptions", false},
+		{"AWS::S3::Bucket", "/metadata", false},
+	}
+	for _, tt := range tests {
+		if got := rules.Ignores(tt.resourceType, tt.path); got != tt.want {
+			t.Errorf("Ignores(%s, %s) = %v, want %v", tt.resourceType, tt.path, got, tt.want)
+		}
+	}
+
+	var none *DriftRules
+	if none.Ignores("AWS::S3::Bucket", "/anything") {
+		t.Error("nil rules should ignore nothing")
+	}
+}
+
+func TestDriftRules_Merge(t *testing.T) {
+	merged := DefaultDriftRules().Merge(&DriftRules{Ignore: map[string][]string{"AWS::EC2::Instance": {"/tags"}}})
+	if !merged.Ignores("AWS::EC2::Instance", "/tags/0") || !merged.Ignores("AWS::EC2::Instance", "/launchTime") {
+		t.Error("merged rules should ignore fields from both rule sets")
+	}
+}
+
+func TestLoadDriftRules(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(valid, []byte(`{"ignore":{"AWS::S3::Bucket":["/lastModified"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadDriftRules(valid)
+	if err != nil {
+		t.Fatalf("LoadDriftRules() error = %v", err)
+	}
+	if !rules.Ignores("AWS::S3::Bucket", "/lastModified") {
+		t.Error("loaded rules should ignore /lastModified")
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"ignore":{"*":["lastModified"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDriftRules(invalid); err == nil {
+		t.Error("LoadDriftRules() should reject patterns that are not JSON pointers")
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte(`{"ignore":{"*":[""]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDriftRules(empty); err == nil {
+		t.Error("LoadDriftRules() should reject the empty pattern")
+	}
+}
+
+func TestDriftRules_EmptyPatternIgnoresNothing(t *testing.T) {
+	rules := &DriftRules{Ignore: map[string][]string{AllResourceTypes: {""}}}
+	if rules.Ignores("AWS::S3::Bucket", "/versioning/status") {
+		t.Error("an empty pattern should not ignore every field")
+	}
+}
+
+func TestInventoryDiff_Drift(t *testing.T) {
+	d := newDriftDiff(t)
+
+	report, err := d.Drift(DefaultDriftRules())
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+
+	// i-2 only changed an ignored field, so it is left out.
+	if len(report.Resources) != 1 || report.Resources[0].ResourceID != "i-1" {
+		t.Fatalf("Drift() resources = %+v, want only i-1", report.Resources)
+	}
+	var paths []string
+	for _, c := range report.Resources[0].Changes {
+		paths = append(paths, c.Path)
+	}
+	want := "/instanceType,/networkInterfaces/0/attachment/status"
+	if strings.Join(paths, ",") != want {
+		t.Errorf("Drift() paths = %v, want %s", paths, want)
+	}
+	if report.FieldChangeCount() != 2 {
+		t.Errorf("FieldChangeCount() = %d, want 2", report.FieldChangeCount())
+	}
+
+	unfiltered, err := d.Drift(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unfiltered.Resources) != 2 || unfiltered.FieldChangeCount() != 5 {
+		t.Errorf("Drift(nil) = %d resources, %d changes, want 2 and 5", len(unfiltered.Resources), unfiltered.FieldChangeCount())
+	}
+}
+
+func TestDriftReportGenerator_Generate(t *testing.T) {
+	report, err := newDriftDiff(t).Drift(DefaultDriftRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewDriftReportGenerator(report).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"# AWS Asset Inventory Drift",
+		"**Resources Changed:** 1\n**Field Changes:** 2",
+		"## AWS::EC2::Instance",
+		"### web (us-east-1)",
+		"**ID:** i-1",
+		"| /instanceType | replace | \"t3.micro\" | \"m5.large\" |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("drift report should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestDriftReportGenerator_NoDrift(t *testing.T) {
+	report := &DriftReport{Resources: []ResourceDrift{}}
+	var buf bytes.Buffer
+	if err := NewDriftReportGenerator(report).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No configuration drift found.") {
+		t.Errorf("empty drift report = %s", buf.String())
+	}
+}
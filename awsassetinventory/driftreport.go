@@ -0,0 +1,131 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+)
+
+// maxDriftValueLen is the longest old or new value shown in a drift report
+// table; longer values are truncated.
+const maxDriftValueLen = 80
+
+// DriftReportGenerator generates markdown change logs from a DriftReport.
+type DriftReportGenerator struct {
+	report *DriftReport
+}
+
+// NewDriftReportGenerator creates a new DriftReportGenerator for the given
+// drift report.
+func NewDriftReportGenerator(r *DriftReport) *DriftReportGenerator {
+	return &DriftReportGenerator{report: r}
+}
+
+// Generate writes a complete markdown drift report to the provided writer.
+func (dg *DriftReportGenerator) Generate(w io.Writer) error {
+	if err := dg.writeHeader(w); err != nil {
+		return err
+	}
+	if len(dg.report.Resources) == 0 {
+		_, err := fmt.Fprintf(w, "No configuration drift found.\n")
+		return err
+	}
+
+	var current ResourceType
+	for _, rd := range dg.report.Resources {
+		if rd.ResourceType != current {
+			current = rd.ResourceType
+			if _, err := fmt.Fprintf(w, "## %s\n\n", current); err != nil {
+				return err
+			}
+		}
+		if err := dg.writeResource(w, rd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dg *DriftReportGenerator) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# AWS Asset Inventory Drift\n\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**From:** %s (%s)\n", dg.report.From.CollectedAt.Format("2006-01-02 15:04:05 UTC"), dg.report.From.Profile)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**To:** %s (%s)\n", dg.report.To.CollectedAt.Format("2006-01-02 15:04:05 UTC"), dg.report.To.Profile)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Resources Changed:** %d\n", len(dg.report.Resources))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Field Changes:** %d\n\n", dg.report.FieldChangeCount())
+	return err
+}
+
+func (dg *DriftReportGenerator) writeResource(w io.Writer, rd ResourceDrift) error {
+	name := rd.ResourceName
+	if name == "" {
+		name = rd.ResourceID
+	}
+	_, err := fmt.Fprintf(w, "### %s (%s)\n\n", escapeMarkdown(name), rd.Region)
+	if err != nil {
+		return err
+	}
+	if name != rd.ResourceID {
+		_, err = fmt.Fprintf(w, "**ID:** %s\n", escapeMarkdown(rd.ResourceID))
+		if err != nil {
+			return err
+		}
+	}
+	if rd.ARN != "" {
+		_, err = fmt.Fprintf(w, "**ARN:** %s\n", escapeMarkdown(rd.ARN))
+		if err != nil {
+			return err
+		}
+	}
+	if name != rd.ResourceID || rd.ARN != "" {
+		_, err = fmt.Fprintf(w, "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "| Path | Change | Old | New |\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|------|--------|-----|-----|\n")
+	if err != nil {
+		return err
+	}
+
+	for _, fc := range rd.Changes {
+		path := fc.Path
+		if path == "" {
+			path = "/"
+		}
+		_, err = fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			escapeMarkdown(path), fc.Op, driftValue(fc.From), driftValue(fc.To))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
+func driftValue(v []byte) string {
+	if len(v) == 0 {
+		return "-"
+	}
+	s := string(v)
+	if len(s) > maxDriftValueLen {
+		s = s[:maxDriftValueLen-3] + "..."
+	}
+	return escapeMarkdown(s)
+}
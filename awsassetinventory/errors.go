@@ -39,6 +39,16 @@ func (ce CollectErrors) Error() string {
 	return sb.String()
 }
 
+// Unwrap returns the individual region errors so errors.Is and errors.As
+// can match any of them.
+func (ce CollectErrors) Unwrap() []error {
+	errs := make([]error, len(ce.Errors))
+	for i, e := range ce.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
 // Regions returns the list of failed regions.
 func (ce CollectErrors) Regions() []Region {
 	regions := make([]Region, len(ce.Errors))
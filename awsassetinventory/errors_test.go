@@ -95,3 +95,17 @@ func TestRegionError_ErrorsAs(t *testing.T) {
 		t.Error("errors.Is should match underlying error through Unwrap")
 	}
 }
+
+func TestCollectErrors_ErrorsIs(t *testing.T) {
+	underlying := errors.New("underlying")
+	ce := CollectErrors{
+		Errors: []RegionError{
+			{Region: Region("us-east-1"), Err: errors.New("other")},
+			{Region: Region("us-west-2"), Err: underlying},
+		},
+	}
+
+	if !errors.Is(ce, underlying) {
+		t.Error("errors.Is should match an underlying region error through Unwrap")
+	}
+}
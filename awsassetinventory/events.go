@@ -0,0 +1,84 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind identifies the kind of a collection progress event.
+type EventKind string
+
+const (
+	EventRegionStarted   EventKind = "region_started"
+	EventTypesDiscovered EventKind = "types_discovered"
+	EventTypeCollected   EventKind = "type_collected"
+	EventRetry           EventKind = "retry"
+	EventThrottled       EventKind = "throttled"
+	EventRegionCompleted EventKind = "region_completed"
+)
+
+// Event describes progress during a collection run. Fields that do not apply
+// to the event's Kind are left at their zero value.
+//
+// Count holds the number of resource types discovered (EventTypesDiscovered),
+// the number of resources collected for a type (EventTypeCollected) or the
+// number of resources collected in the region (EventRegionCompleted).
+// Attempt is the 1-based number of the throttled call (EventThrottled) or of the
+// upcoming call (EventRetry), and Delay is the backoff before that retry.
+// Err is the throttling error, or the region's failure for EventRegionCompleted,
+// and RequestID is the AWS request ID of a throttled call when known.
+type Event struct {
+	Kind         EventKind
+	Time         time.Time
+	Region       Region
+	ResourceType ResourceType
+	Count        int
+	Attempt      int
+	Delay        time.Duration
+	RequestID    string
+	Err          error
+}
+
+// String returns a human-readable, single-line description of the event.
+func (e Event) String() string {
+	switch e.Kind {
+	case EventRegionStarted:
+		return fmt.Sprintf("[%s] Starting collection", e.Region)
+	case EventTypesDiscovered:
+		return fmt.Sprintf("[%s] Found %d resource types", e.Region, e.Count)
+	case EventTypeCollected:
+		return fmt.Sprintf("[%s] Collected %d %s", e.Region, e.Count, e.ResourceType)
+	case EventRetry:
+		return fmt.Sprintf("[%s] Retrying %s (attempt %d) in %s", e.Region, e.target(), e.Attempt, e.Delay.Round(time.Millisecond))
+	case EventThrottled:
+		return fmt.Sprintf("[%s] Throttled on %s (attempt %d): %v", e.Region, e.target(), e.Attempt, e.Err)
+	case EventRegionCompleted:
+		if e.Err != nil {
+			return fmt.Sprintf("[%s] Failed after %d resources: %v", e.Region, e.Count, e.Err)
+		}
+		return fmt.Sprintf("[%s] Completed with %d resources", e.Region, e.Count)
+	default:
+		return fmt.Sprintf("[%s] %s", e.Region, e.Kind)
+	}
+}
+
+func (e Event) target() string {
+	if e.ResourceType != "" {
+		return e.ResourceType.String()
+	}
+	return "resource counts"
+}
+
+// Observer receives collection progress events. Regions are collected
+// concurrently, so OnEvent may be called from multiple goroutines at once.
+type Observer interface {
+	OnEvent(Event)
+}
+
+// ObserverFunc adapts an ordinary function to the Observer interface.
+type ObserverFunc func(Event)
+
+// OnEvent calls f(e).
+func (f ObserverFunc) OnEvent(e Event) {
+	f(e)
+}
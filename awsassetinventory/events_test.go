@@ -0,0 +1,47 @@
+package awsassetinventory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvent_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"region started", Event{Kind: EventRegionStarted, Region: "us-east-1"}, "[us-east-1] Starting collection"},
+		{"types discovered", Event{Kind: EventTypesDiscovered, Region: "us-east-1", Count: 12}, "[us-east-1] Found 12 resource types"},
+		{"type collected", Event{Kind: EventTypeCollected, Region: "us-east-1", ResourceType: "AWS::S3::Bucket", Count: 3}, "[us-east-1] Collected 3 AWS::S3::Bucket"},
+		{"retry", Event{Kind: EventRetry, Region: "us-east-1", ResourceType: "AWS::S3::Bucket", Attempt: 2, Delay: 150 * time.Millisecond}, "[us-east-1] Retrying AWS::S3::Bucket (attempt 2) in 150ms"},
+		{"throttled counts", Event{Kind: EventThrottled, Region: "us-east-1", Attempt: 1, Err: errors.New("Rate exceeded")}, "[us-east-1] Throttled on resource counts (attempt 1): Rate exceeded"},
+		{"region completed", Event{Kind: EventRegionCompleted, Region: "us-east-1", Count: 42}, "[us-east-1] Completed with 42 resources"},
+		{"region failed", Event{Kind: EventRegionCompleted, Region: "us-east-1", Count: 5, Err: errors.New("boom")}, "[us-east-1] Failed after 5 resources: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.String(); got != tt.want {
+				t.Errorf("Event.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserverFunc(t *testing.T) {
+	var got []EventKind
+	var o Observer = ObserverFunc(func(e Event) {
+		got = append(got, e.Kind)
+	})
+
+	o.OnEvent(Event{Kind: EventRegionStarted})
+
+	if len(got) != 1 || got[0] != EventRegionStarted {
+		t.Errorf("ObserverFunc received %v, want [%s]", got, EventRegionStarted)
+	}
+	if !strings.Contains(Event{Kind: "custom", Region: "us-east-1"}.String(), "custom") {
+		t.Error("Event.String() should fall back to the kind for unknown events")
+	}
+}
@@ -0,0 +1,166 @@
+package awsassetinventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportColumn is a column of an exported resource table. Its Name is a
+// field as written in a filter expression: type, id, name, region,
+// availabilityZone, account, arn, source, tags.<key> or config.<path>.
+type ExportColumn struct {
+	Name  string
+	field fieldOperand
+}
+
+// DefaultExportColumnNames are the resource fields exported when no
+// columns are chosen.
+var DefaultExportColumnNames = []string{"type", "id", "name", "region", "availabilityZone", "account", "arn"}
+
+// ParseExportColumns parses column names. The name tags.* expands to a
+// column for every tag key used by resources, in sorted order.
+func ParseExportColumns(names []string, resources []Resource) ([]ExportColumn, error) {
+	var columns []ExportColumn
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "tags.*" {
+			columns = append(columns, tagColumns(resources)...)
+			continue
+		}
+		col, err := parseExportColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// DefaultExportColumns returns the DefaultExportColumnNames followed by a
+// column for every tag key used by resources.
+func DefaultExportColumns(resources []Resource) []ExportColumn {
+	columns := make([]ExportColumn, len(DefaultExportColumnNames))
+	for i, name := range DefaultExportColumnNames {
+		columns[i] = ExportColumn{Name: name, field: fieldOperand{field: filterFields[name]}}
+	}
+	return append(columns, tagColumns(resources)...)
+}
+
+func parseExportColumn(name string) (ExportColumn, error) {
+	tokens, err := lexFilter(name)
+	if err != nil {
+		return ExportColumn{}, fmt.Errorf("invalid column %q: %w", name, err)
+	}
+	p := &filterParser{tokens: tokens}
+	operand, err := p.parseOperand()
+	if err != nil {
+		return ExportColumn{}, fmt.Errorf("invalid column %q: %w", name, err)
+	}
+	field, ok := operand.(fieldOperand)
+	if !ok || p.peek().kind != tokEOF {
+		return ExportColumn{}, fmt.Errorf("invalid column %q: must be a resource field, tags.<key> or config.<path>", name)
+	}
+	return ExportColumn{Name: name, field: field}, nil
+}
+
+// tagColumns returns a tags.<key> column for every tag key of resources.
+func tagColumns(resources []Resource) []ExportColumn {
+	seen := make(map[string]bool)
+	for _, r := range resources {
+		for k := range r.Tags {
+			seen[k] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	columns := make([]ExportColumn, len(keys))
+	for i, k := range keys {
+		columns[i] = ExportColumn{Name: "tags." + k, field: fieldOperand{field: "tags", path: []any{k}}}
+	}
+	return columns
+}
+
+// Value returns the column's value for r as text: strings as they are,
+// other configuration values as compact JSON, and "" when absent.
+func (c ExportColumn) Value(r Resource) string {
+	return exportValue(c.field.value(&filterEnv{resource: &r}))
+}
+
+func exportValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
+
+// WriteCSV writes resources as CSV with a header row of column names and a
+// row per resource, quoting fields as RFC 4180 requires. Values that a
+// spreadsheet would run as a formula, those starting with =, +, -, @, a tab
+// or a carriage return, are prefixed with ' so they are shown as text; plain
+// numbers such as -1 are left as they are.
+func WriteCSV(w io.Writer, columns []ExportColumn, resources []Resource) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, r := range resources {
+		env := &filterEnv{resource: &r}
+		for i, c := range columns {
+			row[i] = csvCell(exportValue(c.field.value(env)))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell guards v against formula injection when the CSV file is opened
+// in a spreadsheet.
+func csvCell(v string) string {
+	if v == "" || !strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return v
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return "'" + v
+}
+
+// ExportFileName returns a file name for the resources of resourceType,
+// such as AWS_EC2_Instance.csv for ext ".csv".
+func ExportFileName(resourceType ResourceType, ext string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ReplaceAll(resourceType.String(), "::", "_"))
+	return name + ext
+}
@@ -0,0 +1,124 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func exportTestResources() []Resource {
+	return []Resource{
+		{
+			ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: `web "blue", east`, Region: "us-east-1", AccountID: "111111111111",
+			Configuration: json.RawMessage(`{"instanceType":"m5.large","cpuOptions":{"coreCount":2},"securityGroups":[{"groupId":"sg-1"}],"note":"line1\nline2"}`),
+			Tags:          map[string]string{"env": "prod", "owner": "web"},
+		},
+		{
+			ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1", AccountID: "111111111111",
+			Tags: map[string]string{"cost-center": "cc-1"},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	resources := exportTestResources()
+	columns, err := ParseExportColumns([]string{"id", "name", "tags.*", "config.instanceType", "config.cpuOptions.coreCount", "config.securityGroups", "config.note"}, resources)
+	if err != nil {
+		t.Fatalf("ParseExportColumns() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, columns, resources); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	// Read the output back to check that quoting round-trips.
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	want := [][]string{
+		{"id", "name", "tags.cost-center", "tags.env", "tags.owner", "config.instanceType", "config.cpuOptions.coreCount", "config.securityGroups", "config.note"},
+		{"i-1", `web "blue", east`, "", "prod", "web", "m5.large", "2", `[{"groupId":"sg-1"}]`, "line1\nline2"},
+		{"logs", "", "cc-1", "", "", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("WriteCSV() records =\n%q\nwant\n%q", records, want)
+	}
+}
+
+func TestWriteCSV_EscapesFormulas(t *testing.T) {
+	resources := []Resource{{
+		ResourceID: "i-1",
+		Tags: map[string]string{
+			"a": "=HYPERLINK(\"http://evil\")",
+			"b": "+1+cmd|' /C calc'!A0",
+			"c": "-2+3",
+			"d": "@SUM(1,2)",
+			"e": "\t=1",
+			"f": "-1",
+			"g": "-0.5",
+			"h": "team-a",
+		},
+	}}
+	columns, err := ParseExportColumns([]string{"tags.*"}, resources)
+	if err != nil {
+		t.Fatalf("ParseExportColumns() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, columns, resources); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	want := []string{
+		"'=HYPERLINK(\"http://evil\")",
+		"'+1+cmd|' /C calc'!A0",
+		"'-2+3",
+		"'@SUM(1,2)",
+		"'\t=1",
+		"-1",
+		"-0.5",
+		"team-a",
+	}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("WriteCSV() row =\n%q\nwant\n%q", records[1], want)
+	}
+}
+
+func TestDefaultExportColumns(t *testing.T) {
+	columns := DefaultExportColumns(exportTestResources())
+	var names []string
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	want := []string{"type", "id", "name", "region", "availabilityZone", "account", "arn", "tags.cost-center", "tags.env", "tags.owner"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("DefaultExportColumns() = %v, want %v", names, want)
+	}
+	if got := columns[0].Value(exportTestResources()[1]); got != "AWS::S3::Bucket" {
+		t.Errorf("type column Value() = %q", got)
+	}
+}
+
+func TestParseExportColumns_Invalid(t *testing.T) {
+	for _, name := range []string{"owner", `"literal"`, "type == 1", "config.", ""} {
+		if _, err := ParseExportColumns([]string{name}, nil); err == nil {
+			t.Errorf("ParseExportColumns(%q) should fail", name)
+		}
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	if got := ExportFileName("AWS::EC2::Instance", ".csv"); got != "AWS_EC2_Instance.csv" {
+		t.Errorf("ExportFileName() = %s", got)
+	}
+	if got := ExportFileName("Custom::My/Type", ".csv"); got != "Custom_My_Type.csv" {
+		t.Errorf("ExportFileName() = %s", got)
+	}
+}
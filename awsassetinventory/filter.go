@@ -0,0 +1,709 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrCountsOnlyFilter is returned by Inventory.Filter for a counts-only
+// inventory, which has no resources to filter.
+var ErrCountsOnlyFilter = errors.New("cannot filter a counts-only inventory")
+
+// FilterError reports a syntax error in a filter expression. Pos is the
+// 0-based byte offset in the expression where the error was detected.
+type FilterError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Filter is a compiled filter expression that selects resources. Create one
+// with ParseFilter.
+//
+// An expression compares resource fields with literals and combines the
+// comparisons with &&, || and !, grouping with parentheses:
+//
+//	type == "AWS::EC2::Instance" && config.instanceType startsWith "m5" && tags.env == "prod"
+//
+// The fields are type, id, name, region, availabilityZone, account, arn and
+// source (or their JSON names, such as resourceType), tags.<key> and
+// config.<path>. Configuration paths use dots for object keys and [n] for
+// array elements; keys that are not plain identifiers are written as
+// ["key"], as in tags["aws:cloudformation:stack-name"]. A field that is
+// absent, including a resource without a name, availability zone, account,
+// ARN or source, has the value null.
+//
+// Literals are strings in single or double quotes, numbers, true, false,
+// null and lists such as ["a", "b"]. The operators are ==, !=, <, <=, >,
+// >=, contains (substring, list element or object key), startsWith,
+// endsWith, matches (a regular expression literal) and in (list
+// membership). A field on its own is true when it is present and not
+// false, zero or empty, so !tags.owner selects untagged resources.
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+// ParseFilter compiles expr. It returns a *FilterError when expr is not a
+// valid expression.
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return &Filter{expr: expr, root: root}, nil
+}
+
+// String returns the expression the filter was parsed from.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Match reports whether r satisfies the filter.
+func (f *Filter) Match(r Resource) bool {
+	return f.root.eval(&filterEnv{resource: &r})
+}
+
+// Filter returns a copy of the inventory holding only the resources that
+// match expr. Counts, Mismatches and Failures describe the whole collection,
+// so they are not carried over; RegionStatus and Sources are.
+func (inv *Inventory) Filter(expr string) (*Inventory, error) {
+	if inv.CountsOnly {
+		return nil, ErrCountsOnlyFilter
+	}
+	f, err := ParseFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &Inventory{
+		CollectedAt:  inv.CollectedAt,
+		Profile:      inv.Profile,
+		Regions:      inv.Regions,
+		Incomplete:   inv.Incomplete,
+		RegionStatus: inv.RegionStatus,
+		Sources:      inv.Sources,
+		Resources:    make([]Resource, 0),
+	}
+	for _, r := range inv.Resources {
+		if f.Match(r) {
+			filtered.AddResource(r)
+		}
+	}
+	return filtered, nil
+}
+
+// filterEnv is the resource a filter is evaluated against. Its configuration
+// is decoded on first use.
+type filterEnv struct {
+	resource *Resource
+	config   any
+	decoded  bool
+}
+
+func (env *filterEnv) configuration() any {
+	if !env.decoded {
+		env.decoded = true
+		if len(env.resource.Configuration) > 0 {
+			// Configuration that is not valid JSON behaves as if absent.
+			_ = json.Unmarshal(env.resource.Configuration, &env.config)
+		}
+	}
+	return env.config
+}
+
+// Lexer
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+	str  string
+	num  float64
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.str)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// filterPunct lists the punctuation tokens, longest first.
+var filterPunct = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			s, n, err := lexFilterString(expr[i:])
+			if err != nil {
+				return nil, &FilterError{Pos: i, Msg: err.Error()}
+			}
+			tokens = append(tokens, filterToken{kind: tokString, text: expr[i : i+n], pos: i, str: s})
+			i += n
+		case isDigit(c) || (c == '-' && i+1 < len(expr) && isDigit(expr[i+1])):
+			start := i
+			i++
+			for i < len(expr) && (isDigit(expr[i]) || expr[i] == '.') {
+				i++
+			}
+			num, err := strconv.ParseFloat(expr[start:i], 64)
+			if err != nil {
+				return nil, &FilterError{Pos: start, Msg: fmt.Sprintf("invalid number %q", expr[start:i])}
+			}
+			tokens = append(tokens, filterToken{kind: tokNumber, text: expr[start:i], pos: start, num: num})
+		case isLetter(c):
+			start := i
+			for i < len(expr) && (isLetter(expr[i]) || isDigit(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: tokIdent, text: expr[start:i], pos: start})
+		default:
+			matched := false
+			for _, p := range filterPunct {
+				if strings.HasPrefix(expr[i:], p) {
+					tokens = append(tokens, filterToken{kind: tokPunct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &FilterError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, filterToken{kind: tokEOF, pos: len(expr)}), nil
+}
+
+// lexFilterString reads the quoted string at the start of s and returns its
+// value and length. Backslash escapes the next character.
+func lexFilterString(s string) (string, int, error) {
+	quote := s[0]
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", 0, errors.New("unterminated string")
+			}
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(s[i])
+			}
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Parser
+
+// filterFields maps the names a filter may use for the top-level resource
+// fields to their canonical names.
+var filterFields = map[string]string{
+	"type":             "type",
+	"resourceType":     "type",
+	"id":               "id",
+	"resourceId":       "id",
+	"name":             "name",
+	"resourceName":     "name",
+	"region":           "region",
+	"awsRegion":        "region",
+	"availabilityZone": "availabilityZone",
+	"account":          "account",
+	"accountId":        "account",
+	"arn":              "arn",
+	"source":           "source",
+	"tags":             "tags",
+	"config":           "config",
+	"configuration":    "config",
+}
+
+// filterOperators are the comparison operators, punctuation and keyword.
+var filterOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"contains": true, "startsWith": true, "endsWith": true, "matches": true, "in": true,
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) accept(punct string) bool {
+	if t := p.peek(); t.kind == tokPunct && t.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(punct string) error {
+	if !p.accept(punct) {
+		t := p.peek()
+		return &FilterError{Pos: t.pos, Msg: fmt.Sprintf("expected %q, found %s", punct, t)}
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	start := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if (op.kind != tokPunct && op.kind != tokIdent) || !filterOperators[op.text] {
+		if _, ok := left.(fieldOperand); ok {
+			return truthyNode{left}, nil
+		}
+		return nil, &FilterError{Pos: op.pos, Msg: fmt.Sprintf("expected a comparison after %s, found %s", start, op)}
+	}
+	p.next()
+
+	rightTok := p.peek()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := compareNode{op: op.text, left: left, right: right}
+	if op.text == "matches" {
+		lit, ok := right.(literalOperand)
+		s, isString := lit.v.(string)
+		if !ok || !isString {
+			return nil, &FilterError{Pos: rightTok.pos, Msg: "matches requires a string literal pattern"}
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, &FilterError{Pos: rightTok.pos, Msg: fmt.Sprintf("invalid pattern: %v", err)}
+		}
+		cmp.re = re
+	}
+	return cmp, nil
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokString:
+		return literalOperand{t.str}, nil
+	case t.kind == tokNumber:
+		return literalOperand{t.num}, nil
+	case t.kind == tokPunct && t.text == "[":
+		return p.parseList()
+	case t.kind == tokIdent:
+		switch t.text {
+		case "true":
+			return literalOperand{true}, nil
+		case "false":
+			return literalOperand{false}, nil
+		case "null":
+			return literalOperand{nil}, nil
+		}
+		field, ok := filterFields[t.text]
+		if !ok {
+			return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("unknown field %q", t.text)}
+		}
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > 0 && field != "tags" && field != "config" {
+			return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("field %q has no subfields", t.text)}
+		}
+		return fieldOperand{field: field, path: path}, nil
+	default:
+		return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("expected a field or value, found %s", t)}
+	}
+}
+
+func (p *filterParser) parseList() (filterOperand, error) {
+	var items []any
+	if p.accept("]") {
+		return literalOperand{items}, nil
+	}
+	for {
+		t := p.peek()
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := item.(literalOperand)
+		if !ok {
+			return nil, &FilterError{Pos: t.pos, Msg: "lists may only contain literals"}
+		}
+		items = append(items, lit.v)
+		if p.accept("]") {
+			return literalOperand{items}, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parsePath reads the .key, [n] and ["key"] accessors following a field.
+func (p *filterParser) parsePath() ([]any, error) {
+	var path []any
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("expected a key after \".\", found %s", t)}
+			}
+			path = append(path, t.text)
+		case p.accept("["):
+			t := p.next()
+			switch {
+			case t.kind == tokString:
+				path = append(path, t.str)
+			case t.kind == tokNumber && t.num >= 0 && t.num == float64(int(t.num)):
+				path = append(path, int(t.num))
+			default:
+				return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("expected a key or index, found %s", t)}
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+// Evaluation
+//
+// Values are those produced by encoding/json: string, float64, bool, nil,
+// []any and map[string]any.
+
+type filterNode interface {
+	eval(env *filterEnv) bool
+}
+
+type filterOperand interface {
+	value(env *filterEnv) any
+}
+
+type orNode struct{ left, right filterNode }
+
+func (n orNode) eval(env *filterEnv) bool { return n.left.eval(env) || n.right.eval(env) }
+
+type andNode struct{ left, right filterNode }
+
+func (n andNode) eval(env *filterEnv) bool { return n.left.eval(env) && n.right.eval(env) }
+
+type notNode struct{ operand filterNode }
+
+func (n notNode) eval(env *filterEnv) bool { return !n.operand.eval(env) }
+
+type truthyNode struct{ operand filterOperand }
+
+func (n truthyNode) eval(env *filterEnv) bool {
+	switch v := n.operand.value(env).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+type compareNode struct {
+	op          string
+	left, right filterOperand
+	re          *regexp.Regexp
+}
+
+func (n compareNode) eval(env *filterEnv) bool {
+	left := n.left.value(env)
+	right := n.right.value(env)
+
+	switch n.op {
+	case "==":
+		return filterEqual(left, right)
+	case "!=":
+		return !filterEqual(left, right)
+	case "<", "<=", ">", ">=":
+		c, ok := filterCompare(left, right)
+		if !ok {
+			return false
+		}
+		switch n.op {
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		default:
+			return c >= 0
+		}
+	case "contains":
+		return filterContains(left, right)
+	case "in":
+		return filterContains(right, left)
+	case "startsWith", "endsWith":
+		s, ok1 := left.(string)
+		affix, ok2 := right.(string)
+		if !ok1 || !ok2 {
+			return false
+		}
+		if n.op == "startsWith" {
+			return strings.HasPrefix(s, affix)
+		}
+		return strings.HasSuffix(s, affix)
+	case "matches":
+		s, ok := left.(string)
+		return ok && n.re.MatchString(s)
+	}
+	return false
+}
+
+type literalOperand struct{ v any }
+
+func (o literalOperand) value(*filterEnv) any { return o.v }
+
+// fieldOperand is a resource field, followed by a path of object keys
+// (string) and array indexes (int) for tags and config.
+type fieldOperand struct {
+	field string
+	path  []any
+}
+
+func (o fieldOperand) value(env *filterEnv) any {
+	r := env.resource
+	var v any
+	switch o.field {
+	case "type":
+		return r.ResourceType.String()
+	case "id":
+		return r.ResourceID
+	case "name":
+		return optionalField(r.ResourceName)
+	case "region":
+		return r.Region.String()
+	case "availabilityZone":
+		return optionalField(r.AvailabilityZone)
+	case "account":
+		return optionalField(r.AccountID)
+	case "arn":
+		return optionalField(r.ARN)
+	case "source":
+		return optionalField(r.Source)
+	case "tags":
+		tags := make(map[string]any, len(r.Tags))
+		for k, val := range r.Tags {
+			tags[k] = val
+		}
+		v = tags
+	case "config":
+		v = env.configuration()
+	}
+
+	for _, step := range o.path {
+		switch key := step.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = m[key]
+		case int:
+			list, ok := v.([]any)
+			if !ok || key >= len(list) {
+				return nil
+			}
+			v = list[key]
+		}
+	}
+	return v
+}
+
+// optionalField returns s, or nil when the field it holds is absent.
+func optionalField(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func filterEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// filterCompare orders two numbers or two strings.
+func filterCompare(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	return 0, false
+}
+
+// filterContains reports whether container, a string, list or object,
+// contains item as a substring, element or key respectively.
+func filterContains(container, item any) bool {
+	switch c := container.(type) {
+	case string:
+		s, ok := item.(string)
+		return ok && strings.Contains(c, s)
+	case []any:
+		for _, e := range c {
+			if filterEqual(e, item) {
+				return true
+			}
+		}
+	case map[string]any:
+		key, ok := item.(string)
+		if !ok {
+			return false
+		}
+		_, found := c[key]
+		return found
+	}
+	return false
+}
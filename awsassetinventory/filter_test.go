@@ -0,0 +1,153 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func filterTestResources() []Resource {
+	return []Resource{
+		{
+			ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1",
+			AvailabilityZone: "us-east-1a", AccountID: "111111111111", ARN: "arn:aws:ec2:us-east-1:111111111111:instance/i-1",
+			Configuration: json.RawMessage(`{"instanceType":"m5.large","cpuOptions":{"coreCount":2},"securityGroups":[{"groupId":"sg-1"}],"ebsOptimized":true}`),
+			Tags:          map[string]string{"env": "prod", "aws:cloudformation:stack-name": "web-stack"},
+		},
+		{
+			ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2", AccountID: "222222222222",
+			Configuration: json.RawMessage(`{"instanceType":"t3.micro","cpuOptions":{"coreCount":1},"securityGroups":[]}`),
+			Tags:          map[string]string{"env": "dev"},
+		},
+		{
+			ResourceType: "AWS::S3::Bucket", ResourceID: "logs", ResourceName: "logs", Region: "us-east-1", AccountID: "111111111111",
+		},
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`type == "AWS::EC2::Instance" && config.instanceType startsWith "m5" && tags.env == "prod"`, []string{"i-1"}},
+		{`type == "AWS::EC2::Instance"`, []string{"i-1", "i-2"}},
+		{`resourceType != 'AWS::EC2::Instance'`, []string{"logs"}},
+		{`region == "us-east-1" || account == "222222222222"`, []string{"i-1", "i-2", "logs"}},
+		{`!(region == "us-east-1")`, []string{"i-2"}},
+		{`config.cpuOptions.coreCount >= 2`, []string{"i-1"}},
+		{`config.cpuOptions.coreCount < 2`, []string{"i-2"}},
+		{`config.securityGroups[0].groupId == "sg-1"`, []string{"i-1"}},
+		{`config.securityGroups`, []string{"i-1"}},
+		{`config.ebsOptimized`, []string{"i-1"}},
+		{`!tags.env`, []string{"logs"}},
+		{`tags["aws:cloudformation:stack-name"] endsWith "-stack"`, []string{"i-1"}},
+		{`tags contains "env"`, []string{"i-1", "i-2"}},
+		{`arn contains ":instance/"`, []string{"i-1"}},
+		{`region in ["us-west-2", "eu-west-1"]`, []string{"i-2"}},
+		{`name matches "^w.b$"`, []string{"i-1"}},
+		{`config.missing == null && type == "AWS::S3::Bucket"`, []string{"logs"}},
+		{`name == null`, []string{"i-2"}},
+		{`availabilityZone == null && arn == null`, []string{"i-2", "logs"}},
+		{`name != null`, []string{"i-1", "logs"}},
+		{`config.instanceType > 5`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			var got []string
+			for _, r := range filterTestResources() {
+				if f.Match(r) {
+					got = append(got, r.ResourceID)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("matched %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 1},
+		{`type ==`, 8},
+		{`owner == "me"`, 1},
+		{`type == "unterminated`, 9},
+		{`(type == "a"`, 13},
+		{`type == "a" extra`, 13},
+		{`"a"`, 4},
+		{`name matches "("`, 14},
+		{`name matches name`, 14},
+		{`region.code == "x"`, 1},
+		{`type == "a" # comment`, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			var fe *FilterError
+			if !errors.As(err, &fe) {
+				t.Fatalf("ParseFilter() error = %v, want a *FilterError", err)
+			}
+			if fe.Pos+1 != tt.pos {
+				t.Errorf("error position = %d, want %d (%v)", fe.Pos+1, tt.pos, err)
+			}
+		})
+	}
+}
+
+func TestInventory_Filter(t *testing.T) {
+	inv := NewInventory("prod", []Region{"us-east-1", "us-west-2"})
+	inv.Incomplete = true
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Count: 1})
+	for _, r := range filterTestResources() {
+		inv.AddResource(r)
+	}
+
+	filtered, err := inv.Filter(`type == "AWS::S3::Bucket"`)
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if len(filtered.Resources) != 1 || filtered.Resources[0].ResourceID != "logs" {
+		t.Errorf("Filter() resources = %+v", filtered.Resources)
+	}
+	if filtered.Profile != "prod" || !filtered.Incomplete || len(filtered.Regions) != 2 {
+		t.Errorf("Filter() should keep the inventory metadata, got %+v", filtered)
+	}
+	if filtered.Counts != nil {
+		t.Error("Filter() should not carry over counts for the whole collection")
+	}
+	if len(inv.Resources) != 3 {
+		t.Error("Filter() should not modify the original inventory")
+	}
+
+	none, err := inv.Filter(`region == "ap-south-1"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if none.Resources == nil || len(none.Resources) != 0 {
+		t.Errorf("Filter() with no matches should return an empty resource list, got %v", none.Resources)
+	}
+
+	if _, err := inv.Filter(`type ==`); err == nil {
+		t.Error("Filter() should return the parse error")
+	}
+
+	countsOnly := &Inventory{CountsOnly: true}
+	if _, err := countsOnly.Filter(`type == "x"`); !errors.Is(err, ErrCountsOnlyFilter) {
+		t.Errorf("Filter() on counts-only inventory error = %v, want ErrCountsOnlyFilter", err)
+	}
+}
@@ -0,0 +1,150 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+//go:embed htmlreport.tmpl
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// HTMLReportGenerator generates a self-contained HTML report from inventory
+// data, with the sections of the markdown report, bar charts of resources
+// by type and region, and sortable tables. With IncludeDetails it adds a
+// searchable table of every resource with its tags and expandable
+// configuration JSON. Styles and scripts are inlined, so the file works
+// offline and can be sent by email.
+type HTMLReportGenerator struct {
+	inventory      *Inventory
+	IncludeDetails bool
+}
+
+// NewHTMLReportGenerator creates a new HTMLReportGenerator for the given inventory.
+func NewHTMLReportGenerator(inv *Inventory) *HTMLReportGenerator {
+	return &HTMLReportGenerator{inventory: inv}
+}
+
+// htmlCount is a labelled count, with Percent its share of the largest
+// count in a chart.
+type htmlCount struct {
+	Name    string
+	Count   int
+	Percent float64
+}
+
+type htmlRegion struct {
+	Name  string
+	Types []htmlCount
+}
+
+type htmlResource struct {
+	Resource
+	TagList []string
+	Config  string
+}
+
+type htmlReportData struct {
+	Inventory      *Inventory
+	Regions        string
+	Total          int
+	Types          []htmlCount
+	TypeChart      []htmlCount
+	RegionChart    []htmlCount
+	ByRegion       []htmlRegion
+	IncludeDetails bool
+	Resources      []htmlResource
+}
+
+// Generate writes the HTML report to the provided writer.
+func (hg *HTMLReportGenerator) Generate(w io.Writer) error {
+	inv := hg.inventory
+	regionStrings := make([]string, len(inv.Regions))
+	for i, r := range inv.Regions {
+		regionStrings[i] = r.String()
+	}
+
+	data := htmlReportData{
+		Inventory:      inv,
+		Regions:        strings.Join(regionStrings, ", "),
+		Total:          inv.ResourceCount(),
+		IncludeDetails: hg.IncludeDetails,
+	}
+
+	typeCounts := inv.ResourceCountByType()
+	for _, rt := range sortedResourceTypes(typeCounts) {
+		data.Types = append(data.Types, htmlCount{Name: rt.String(), Count: typeCounts[rt]})
+	}
+	data.TypeChart = chartCounts(data.Types)
+
+	var regionCounts []htmlCount
+	countsByRegion := inv.ResourceCountByTypeAndRegion()
+	for _, region := range sortedRegions(countsByRegion) {
+		hr := htmlRegion{Name: region.String()}
+		total := 0
+		for _, rt := range sortedResourceTypes(countsByRegion[region]) {
+			n := countsByRegion[region][rt]
+			hr.Types = append(hr.Types, htmlCount{Name: rt.String(), Count: n})
+			total += n
+		}
+		data.ByRegion = append(data.ByRegion, hr)
+		regionCounts = append(regionCounts, htmlCount{Name: region.String(), Count: total})
+	}
+	data.RegionChart = chartCounts(regionCounts)
+
+	if hg.IncludeDetails && !inv.CountsOnly {
+		grouped := inv.ResourcesByType()
+		for _, rt := range sortedResourceTypes(typeCounts) {
+			resources := grouped[rt]
+			sortResources(resources)
+			for _, r := range resources {
+				data.Resources = append(data.Resources, htmlResource{Resource: r, TagList: tagList(r.Tags), Config: indentConfig(r.Configuration)})
+			}
+		}
+	}
+
+	return htmlReport.Execute(w, data)
+}
+
+// chartCounts returns counts ordered largest first, each with its
+// percentage of the largest.
+func chartCounts(counts []htmlCount) []htmlCount {
+	chart := make([]htmlCount, len(counts))
+	copy(chart, counts)
+	sort.SliceStable(chart, func(i, j int) bool { return chart[i].Count > chart[j].Count })
+	for i := range chart {
+		if chart[0].Count > 0 {
+			chart[i].Percent = float64(chart[i].Count) * 100 / float64(chart[0].Count)
+		}
+	}
+	return chart
+}
+
+// tagList returns tags as "key=value" strings sorted by key.
+func tagList(tags map[string]string) []string {
+	list := make([]string, 0, len(tags))
+	for k, v := range tags {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// indentConfig pretty-prints a configuration document, or returns it as it
+// is when it is not valid JSON.
+func indentConfig(config json.RawMessage) string {
+	if len(config) == 0 || string(config) == "null" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, config, "", "  "); err != nil {
+		return string(config)
+	}
+	return buf.String()
+}
@@ -0,0 +1,92 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func htmlTestInventory() *Inventory {
+	return &Inventory{
+		CollectedAt: time.Date(2026, 1, 7, 15, 30, 0, 0, time.UTC),
+		Profile:     "prod",
+		Regions:     []Region{"us-east-1", "us-west-2"},
+		Incomplete:  true,
+		Resources: []Resource{
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "<script>alert(1)</script>", Region: "us-east-1",
+				Configuration: json.RawMessage(`{"instanceType":"m5.large"}`), Tags: map[string]string{"owner": "web", "env": "prod"}},
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2"},
+			{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"},
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-3", Region: "us-east-1"},
+		},
+	}
+}
+
+func TestHTMLReportGenerator_Generate(t *testing.T) {
+	hg := NewHTMLReportGenerator(htmlTestInventory())
+	hg.IncludeDetails = true
+
+	var buf bytes.Buffer
+	if err := hg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"<h1>AWS Asset Inventory Report</h1>",
+		"<dt>Collected</dt><dd>2026-01-07 15:30:00 UTC</dd>",
+		"<dt>Regions</dt><dd>us-east-1, us-west-2</dd>",
+		"Incomplete (collection failed in some regions or was interrupted)",
+		"<dt>Total Resources</dt><dd>4</dd>",
+		// Charts are scaled to the largest count.
+		`title="AWS::EC2::Instance">AWS::EC2::Instance</span><span class="bar" style="width: 100%"></span><span class="value">3</span>`,
+		`title="us-west-2">us-west-2</span><span class="bar" style="width: 33.33`,
+		"<tr><td>AWS::S3::Bucket</td><td class=\"num\">1</td></tr>",
+		"<h3>us-west-2</h3>",
+		`<input type="search" id="search"`,
+		"<td>env=prod<br>owner=web</td>",
+		"<details><summary>JSON</summary><pre>{\n  &#34;instanceType&#34;: &#34;m5.large&#34;\n}</pre></details>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HTML report should contain %q", want)
+		}
+	}
+
+	if strings.Contains(output, "<script>alert(1)</script>") {
+		t.Error("resource fields should be escaped")
+	}
+	for _, external := range []string{"src=", "<link", "http://", "https://"} {
+		if strings.Contains(output, external) {
+			t.Errorf("HTML report should be self-contained, found %q", external)
+		}
+	}
+}
+
+func TestHTMLReportGenerator_WithoutDetails(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewHTMLReportGenerator(htmlTestInventory()).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Resource Details") {
+		t.Error("HTML report should only include details when IncludeDetails is set")
+	}
+}
+
+func TestHTMLReportGenerator_Empty(t *testing.T) {
+	inv := NewInventory("empty", []Region{"us-east-1"})
+	hg := NewHTMLReportGenerator(inv)
+	hg.IncludeDetails = true
+
+	var buf bytes.Buffer
+	if err := hg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{"No resources found.", "No resources to display."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("empty HTML report should contain %q", want)
+		}
+	}
+}
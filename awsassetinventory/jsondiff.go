@@ -0,0 +1,166 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field change operations, named after their JSON Patch (RFC 6902)
+// counterparts.
+const (
+	FieldAdded    = "add"
+	FieldRemoved  = "remove"
+	FieldReplaced = "replace"
+)
+
+// FieldChange is a difference between two JSON documents at a single
+// location, given as a JSON pointer (RFC 6901). From is absent for added
+// fields and To for removed ones.
+type FieldChange struct {
+	Path string          `json:"path"`
+	Op   string          `json:"op"`
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// DiffJSON compares two JSON documents structurally and returns the changed
+// leaves. Objects are compared key by key and arrays index by index, so a
+// change deep inside a document is reported at its own path rather than as
+// a change of the whole document. Changes are sorted by path. Empty input is
+// treated as an absent document.
+func DiffJSON(from, to json.RawMessage) ([]FieldChange, error) {
+	a, aPresent, err := decodeJSONValue(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from document: %w", err)
+	}
+	b, bPresent, err := decodeJSONValue(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to document: %w", err)
+	}
+
+	var changes []FieldChange
+	switch {
+	case aPresent && bPresent:
+		changes = diffJSONValues("", a, b, changes)
+	case aPresent:
+		changes = append(changes, FieldChange{Path: "", Op: FieldRemoved, From: encodeJSONValue(a)})
+	case bPresent:
+		changes = append(changes, FieldChange{Path: "", Op: FieldAdded, To: encodeJSONValue(b)})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return lessPointer(changes[i].Path, changes[j].Path) })
+	return changes, nil
+}
+
+// lessPointer orders JSON pointers token by token, comparing array indexes
+// numerically so that /list/2 sorts before /list/10.
+func lessPointer(a, b string) bool {
+	at, bt := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(at) && i < len(bt); i++ {
+		if at[i] == bt[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(at[i])
+		bn, bErr := strconv.Atoi(bt[i])
+		if aErr == nil && bErr == nil {
+			return an < bn
+		}
+		return at[i] < bt[i]
+	}
+	return len(at) < len(bt)
+}
+
+func decodeJSONValue(data json.RawMessage) (any, bool, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, false, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+func encodeJSONValue(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage(strconv.Quote(fmt.Sprint(v)))
+	}
+	return data
+}
+
+func diffJSONValues(path string, a, b any, changes []FieldChange) []FieldChange {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			return diffJSONObjects(path, av, bv, changes)
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			return diffJSONArrays(path, av, bv, changes)
+		}
+	default:
+		if jsonScalarEqual(a, b) {
+			return changes
+		}
+	}
+	return append(changes, FieldChange{Path: path, Op: FieldReplaced, From: encodeJSONValue(a), To: encodeJSONValue(b)})
+}
+
+func diffJSONObjects(path string, a, b map[string]any, changes []FieldChange) []FieldChange {
+	for key, av := range a {
+		child := path + "/" + escapePointerToken(key)
+		if bv, ok := b[key]; ok {
+			changes = diffJSONValues(child, av, bv, changes)
+		} else {
+			changes = append(changes, FieldChange{Path: child, Op: FieldRemoved, From: encodeJSONValue(av)})
+		}
+	}
+	for key, bv := range b {
+		if _, ok := a[key]; !ok {
+			changes = append(changes, FieldChange{Path: path + "/" + escapePointerToken(key), Op: FieldAdded, To: encodeJSONValue(bv)})
+		}
+	}
+	return changes
+}
+
+func diffJSONArrays(path string, a, b []any, changes []FieldChange) []FieldChange {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		child := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(b):
+			changes = append(changes, FieldChange{Path: child, Op: FieldRemoved, From: encodeJSONValue(a[i])})
+		case i >= len(a):
+			changes = append(changes, FieldChange{Path: child, Op: FieldAdded, To: encodeJSONValue(b[i])})
+		default:
+			changes = diffJSONValues(child, a[i], b[i], changes)
+		}
+	}
+	return changes
+}
+
+// jsonScalarEqual compares decoded JSON scalars. Numbers are compared by
+// their text, so 1 and 1.0 differ, as they do in the source document.
+func jsonScalarEqual(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any, []any:
+		return false
+	case json.Number:
+		bv, ok := b.(json.Number)
+		return ok && av == bv
+	default:
+		return a == b
+	}
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointerToken(s string) string {
+	return pointerEscaper.Replace(s)
+}
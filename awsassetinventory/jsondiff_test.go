@@ -0,0 +1,108 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []FieldChange
+	}{
+		{
+			name: "identical with different key order",
+			from: `{"a":1,"b":{"c":true}}`,
+			to:   `{"b":{"c":true},"a":1}`,
+			want: nil,
+		},
+		{
+			name: "nested replace",
+			from: `{"instanceType":"t3.micro","state":{"name":"running"}}`,
+			to:   `{"instanceType":"m5.large","state":{"name":"running"}}`,
+			want: []FieldChange{{Path: "/instanceType", Op: FieldReplaced, From: json.RawMessage(`"t3.micro"`), To: json.RawMessage(`"m5.large"`)}},
+		},
+		{
+			name: "added and removed keys",
+			from: `{"old":1,"same":2}`,
+			to:   `{"new":{"x":1},"same":2}`,
+			want: []FieldChange{
+				{Path: "/new", Op: FieldAdded, To: json.RawMessage(`{"x":1}`)},
+				{Path: "/old", Op: FieldRemoved, From: json.RawMessage(`1`)},
+			},
+		},
+		{
+			name: "array elements by index",
+			from: `{"tags":["a","b"]}`,
+			to:   `{"tags":["a","c","d"]}`,
+			want: []FieldChange{
+				{Path: "/tags/1", Op: FieldReplaced, From: json.RawMessage(`"b"`), To: json.RawMessage(`"c"`)},
+				{Path: "/tags/2", Op: FieldAdded, To: json.RawMessage(`"d"`)},
+			},
+		},
+		{
+			name: "type change",
+			from: `{"value":"1"}`,
+			to:   `{"value":1}`,
+			want: []FieldChange{{Path: "/value", Op: FieldReplaced, From: json.RawMessage(`"1"`), To: json.RawMessage(`1`)}},
+		},
+		{
+			name: "escaped keys",
+			from: `{"a/b":{"c~d":1}}`,
+			to:   `{"a/b":{"c~d":2}}`,
+			want: []FieldChange{{Path: "/a~1b/c~0d", Op: FieldReplaced, From: json.RawMessage(`1`), To: json.RawMessage(`2`)}},
+		},
+		{
+			name: "large numbers keep their precision",
+			from: `{"id":12345678901234567890}`,
+			to:   `{"id":12345678901234567891}`,
+			want: []FieldChange{{Path: "/id", Op: FieldReplaced, From: json.RawMessage(`12345678901234567890`), To: json.RawMessage(`12345678901234567891`)}},
+		},
+		{
+			name: "whole document added",
+			from: ``,
+			to:   `{"a":1}`,
+			want: []FieldChange{{Path: "", Op: FieldAdded, To: json.RawMessage(`{"a":1}`)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffJSON(json.RawMessage(tt.from), json.RawMessage(tt.to))
+			if err != nil {
+				t.Fatalf("DiffJSON() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DiffJSON() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Path != tt.want[i].Path || got[i].Op != tt.want[i].Op ||
+					string(got[i].From) != string(tt.want[i].From) || string(got[i].To) != string(tt.want[i].To) {
+					t.Errorf("DiffJSON()[%d] = {%s %s %s %s}, want {%s %s %s %s}", i,
+						got[i].Path, got[i].Op, got[i].From, got[i].To,
+						tt.want[i].Path, tt.want[i].Op, tt.want[i].From, tt.want[i].To)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffJSON_Invalid(t *testing.T) {
+	if _, err := DiffJSON(json.RawMessage(`{`), json.RawMessage(`{}`)); err == nil {
+		t.Error("DiffJSON() should reject invalid JSON")
+	}
+}
+
+func TestLessPointer(t *testing.T) {
+	if !lessPointer("/list/2", "/list/10") {
+		t.Error("array indexes should sort numerically")
+	}
+	if !lessPointer("/a", "/a/b") {
+		t.Error("a parent should sort before its children")
+	}
+	if lessPointer("/b", "/a/z") {
+		t.Error("keys should sort lexically")
+	}
+}
@@ -0,0 +1,69 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ReportSummary is the machine-readable form of a report: the inventory's
+// metadata and its resource counts by type, region, account and
+// availability zone. Resources is only set when details are included.
+type ReportSummary struct {
+	CollectedAt  time.Time                       `json:"collectedAt"`
+	Profile      string                          `json:"profile"`
+	Regions      []Region                        `json:"regions"`
+	Incomplete   bool                            `json:"incomplete,omitempty"`
+	CountsOnly   bool                            `json:"countsOnly,omitempty"`
+	Total        int                             `json:"totalResources"`
+	ByType       map[ResourceType]int            `json:"byType"`
+	ByRegion     map[Region]map[ResourceType]int `json:"byRegion"`
+	ByAccount    map[string]int                  `json:"byAccount,omitempty"`
+	ByZone       map[Region]map[string]int       `json:"byAvailabilityZone,omitempty"`
+	Mismatches   []CountMismatch                 `json:"mismatches,omitempty"`
+	RegionStatus []RegionStatus                  `json:"regionStatus,omitempty"`
+	Failures     []ResourceFailure               `json:"failures,omitempty"`
+	Sources      []MergeSource                   `json:"sources,omitempty"`
+	Resources    []Resource                      `json:"resources,omitempty"`
+}
+
+// NewReportSummary summarises inv. With includeDetails the summary lists
+// every resource, sorted by type, region and ID.
+func NewReportSummary(inv *Inventory, includeDetails bool) *ReportSummary {
+	s := &ReportSummary{
+		CollectedAt:  inv.CollectedAt,
+		Profile:      inv.Profile,
+		Regions:      inv.Regions,
+		Incomplete:   inv.Incomplete,
+		CountsOnly:   inv.CountsOnly,
+		Total:        inv.ResourceCount(),
+		ByType:       inv.ResourceCountByType(),
+		ByRegion:     inv.ResourceCountByTypeAndRegion(),
+		ByAccount:    inv.ResourceCountByAccount(),
+		ByZone:       inv.ResourceCountByAvailabilityZone(),
+		Mismatches:   inv.Mismatches,
+		RegionStatus: inv.RegionStatus,
+		Failures:     inv.Failures,
+		Sources:      inv.Sources,
+	}
+	if includeDetails && len(inv.Resources) > 0 {
+		s.Resources = make([]Resource, len(inv.Resources))
+		copy(s.Resources, inv.Resources)
+		SortByTypeRegionID(s.Resources)
+	}
+	return s
+}
+
+// JSONRenderer renders a report as an indented ReportSummary.
+type JSONRenderer struct{}
+
+// Render writes the JSON summary of inv to w.
+func (JSONRenderer) Render(w io.Writer, inv *Inventory, opts RenderOptions) error {
+	data, err := json.MarshalIndent(NewReportSummary(inv, opts.IncludeDetails), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
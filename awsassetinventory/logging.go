@@ -0,0 +1,123 @@
+package awsassetinventory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// discardHandler is a slog.Handler that drops every record. It is used when
+// a Collector has no Logger.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+var discardLogger = slog.New(discardHandler{})
+
+func (c *Collector) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return discardLogger
+}
+
+// logEvent writes a progress event to the Collector's Logger as a structured
+// record. Per-type progress and retries are logged at debug level.
+func (c *Collector) logEvent(e Event) {
+	attrs := []slog.Attr{slog.String("region", e.Region.String())}
+	if e.ResourceType != "" {
+		attrs = append(attrs, slog.String("resource_type", e.ResourceType.String()))
+	}
+
+	level := slog.LevelInfo
+	var msg string
+	switch e.Kind {
+	case EventRegionStarted:
+		msg = "starting region collection"
+	case EventTypesDiscovered:
+		msg = "discovered resource types"
+		attrs = append(attrs, slog.Int("count", e.Count))
+	case EventTypeCollected:
+		level = slog.LevelDebug
+		msg = "collected resource type"
+		attrs = append(attrs, slog.Int("count", e.Count))
+	case EventRetry:
+		level = slog.LevelDebug
+		msg = "retrying AWS Config call"
+		attrs = append(attrs, slog.Int("attempt", e.Attempt), slog.Duration("delay", e.Delay))
+	case EventThrottled:
+		level = slog.LevelWarn
+		msg = "AWS Config call throttled"
+		attrs = append(attrs, slog.Int("attempt", e.Attempt))
+	case EventRegionCompleted:
+		msg = "region collection completed"
+		attrs = append(attrs, slog.Int("resources", e.Count))
+		if e.Err != nil {
+			level = slog.LevelError
+			msg = "region collection failed"
+		}
+	default:
+		msg = string(e.Kind)
+	}
+
+	if e.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", e.RequestID))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+
+	c.logger().LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// logCall records the outcome of a single AWS Config API call attempt at
+// debug level, including the AWS request ID when one is available.
+func (c *Collector) logCall(ctx context.Context, operation string, region Region, resourceType ResourceType, attempt int, requestID string, err error) {
+	log := c.logger()
+	if !log.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.String("region", region.String()),
+	}
+	if resourceType != "" {
+		attrs = append(attrs, slog.String("resource_type", resourceType.String()))
+	}
+	attrs = append(attrs, slog.Int("attempt", attempt))
+	if requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	log.LogAttrs(ctx, slog.LevelDebug, "AWS Config call", attrs...)
+}
+
+// requestIDOf returns the AWS request ID of a call from its error, or from
+// the response metadata when the call succeeded.
+func requestIDOf(metadata middleware.Metadata, err error) string {
+	if err != nil {
+		return requestIDFromError(err)
+	}
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(metadata)
+	return requestID
+}
+
+// requestIDFromError extracts the AWS request ID from an SDK error, if any.
+func requestIDFromError(err error) string {
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.ServiceRequestID()
+	}
+	return ""
+}
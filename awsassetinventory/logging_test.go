@@ -0,0 +1,45 @@
+package awsassetinventory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestRequestIDFromError(t *testing.T) {
+	respErr := &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 400}},
+			Err:      errors.New("ThrottlingException"),
+		},
+		RequestID: "abc-123",
+	}
+
+	if got := requestIDFromError(respErr); got != "abc-123" {
+		t.Errorf("requestIDFromError() = %q, want abc-123", got)
+	}
+	if got := requestIDFromError(RegionError{Region: "us-east-1", Err: respErr}); got != "abc-123" {
+		t.Errorf("requestIDFromError() wrapped = %q, want abc-123", got)
+	}
+	if got := requestIDFromError(errors.New("plain")); got != "" {
+		t.Errorf("requestIDFromError() plain = %q, want empty", got)
+	}
+}
+
+func TestCollector_LoggerDefaultsToDiscard(t *testing.T) {
+	c := NewCollector("test", nil)
+	if c.logger().Enabled(context.Background(), slog.LevelError) {
+		t.Error("logger() without a Logger should discard all records")
+	}
+
+	custom := slog.Default()
+	c.Logger = custom
+	if c.logger() != custom {
+		t.Error("logger() should return the injected Logger")
+	}
+}
@@ -0,0 +1,166 @@
+package awsassetinventory
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MergeSource describes one inventory that went into a merged inventory.
+// Resources counts its resources before duplicates were removed.
+type MergeSource struct {
+	Name        string    `json:"name"`
+	CollectedAt time.Time `json:"collectedAt"`
+	Profile     string    `json:"profile"`
+	Regions     []Region  `json:"regions"`
+	Accounts    []string  `json:"accounts,omitempty"`
+	Incomplete  bool      `json:"incomplete,omitempty"`
+	CountsOnly  bool      `json:"countsOnly,omitempty"`
+	Resources   int       `json:"resources"`
+}
+
+// MergeInput is an inventory to merge and the name it is recorded under,
+// such as the file it was read from. An empty Name defaults to the
+// inventory's profile.
+type MergeInput struct {
+	Name      string
+	Inventory *Inventory
+}
+
+// Merge combines inventories, for example ones collected per account in
+// parallel, into one.
+//
+// Regions are united in the order first seen, and resources are
+// de-duplicated by Key. When the same resource appears more than once, the
+// most recently collected copy is kept, or the later input's on a tie. Every
+// resource records the source it came from, and Sources describes each
+// input. Inputs that were themselves merged contribute their own sources,
+// and their resources count as collected when their own source was.
+//
+// The merged inventory takes the newest collection time and lists every
+// profile, and is incomplete if any input is. Mismatches, region statuses
+// and failures describe each input's own collection, so they are
+// concatenated rather than de-duplicated.
+//
+// If any input is counts-only, so is the merged inventory: it has the
+// counts of the counts-only inputs, and the de-duplicated resources of the
+// others counted by type and region in place of their resources and
+// recorded counts.
+func Merge(inputs ...MergeInput) *Inventory {
+	merged := NewInventory("", nil)
+	merged.CollectedAt = time.Time{}
+
+	var profiles []string
+	seenProfiles := make(map[string]bool)
+	seenRegions := make(map[Region]bool)
+	index := make(map[string]int)
+	keptFrom := make(map[string]time.Time)
+	var counts []ResourceTypeCount
+
+	for _, in := range inputs {
+		inv := in.Inventory
+		if inv.CollectedAt.After(merged.CollectedAt) {
+			merged.CollectedAt = inv.CollectedAt
+		}
+		for _, p := range strings.Split(inv.Profile, ", ") {
+			if p != "" && !seenProfiles[p] {
+				seenProfiles[p] = true
+				profiles = append(profiles, p)
+			}
+		}
+		for _, r := range inv.Regions {
+			if !seenRegions[r] {
+				seenRegions[r] = true
+				merged.Regions = append(merged.Regions, r)
+			}
+		}
+		merged.Incomplete = merged.Incomplete || inv.Incomplete
+		if inv.CountsOnly {
+			merged.CountsOnly = true
+			counts = append(counts, inv.Counts...)
+		} else {
+			merged.Counts = append(merged.Counts, inv.Counts...)
+		}
+		merged.Mismatches = append(merged.Mismatches, inv.Mismatches...)
+		merged.RegionStatus = append(merged.RegionStatus, inv.RegionStatus...)
+		merged.Failures = append(merged.Failures, inv.Failures...)
+
+		name := in.Name
+		if name == "" {
+			name = inv.Profile
+		}
+		if len(inv.Sources) > 0 {
+			merged.Sources = append(merged.Sources, inv.Sources...)
+		} else {
+			merged.Sources = append(merged.Sources, newMergeSource(name, inv))
+		}
+
+		sourceTimes := make(map[string]time.Time, len(inv.Sources))
+		for _, s := range inv.Sources {
+			sourceTimes[s.Name] = s.CollectedAt
+		}
+		for _, r := range inv.Resources {
+			if r.Source == "" {
+				r.Source = name
+			}
+			collectedAt, ok := sourceTimes[r.Source]
+			if !ok {
+				collectedAt = inv.CollectedAt
+			}
+			key := r.Key()
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(merged.Resources)
+				keptFrom[key] = collectedAt
+				merged.Resources = append(merged.Resources, r)
+				continue
+			}
+			if !collectedAt.Before(keptFrom[key]) {
+				keptFrom[key] = collectedAt
+				merged.Resources[i] = r
+			}
+		}
+	}
+
+	merged.Profile = strings.Join(profiles, ", ")
+	if merged.CountsOnly {
+		byRegion := make(map[Region]map[ResourceType]int)
+		for _, r := range merged.Resources {
+			if byRegion[r.Region] == nil {
+				byRegion[r.Region] = make(map[ResourceType]int)
+			}
+			byRegion[r.Region][r.ResourceType]++
+		}
+		for _, region := range sortedRegions(byRegion) {
+			for _, rt := range sortedResourceTypes(byRegion[region]) {
+				counts = append(counts, ResourceTypeCount{ResourceType: rt, Region: region, Count: byRegion[region][rt]})
+			}
+		}
+		merged.Counts = counts
+		merged.Resources = make([]Resource, 0)
+	}
+	return merged
+}
+
+func newMergeSource(name string, inv *Inventory) MergeSource {
+	seen := make(map[string]bool)
+	var accounts []string
+	for _, r := range inv.Resources {
+		if r.AccountID != "" && !seen[r.AccountID] {
+			seen[r.AccountID] = true
+			accounts = append(accounts, r.AccountID)
+		}
+	}
+	sort.Strings(accounts)
+
+	return MergeSource{
+		Name:        name,
+		CollectedAt: inv.CollectedAt,
+		Profile:     inv.Profile,
+		Regions:     inv.Regions,
+		Accounts:    accounts,
+		Incomplete:  inv.Incomplete,
+		CountsOnly:  inv.CountsOnly,
+		Resources:   inv.ResourceCount(),
+	}
+}
@@ -0,0 +1,195 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mergeTestInventories() (*Inventory, *Inventory) {
+	prod := NewInventory("prod", []Region{"us-east-1"})
+	prod.CollectedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	prod.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", AccountID: "111111111111", ResourceName: "old"})
+	prod.AddResource(Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-1", Region: "us-east-1", AccountID: "111111111111"})
+
+	shared := NewInventory("shared", []Region{"eu-west-1", "us-east-1"})
+	shared.CollectedAt = time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	shared.Incomplete = true
+	shared.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", AccountID: "111111111111", ResourceName: "new"})
+	shared.AddResource(Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-2", Region: "eu-west-1", AccountID: "222222222222"})
+	return prod, shared
+}
+
+func TestMerge(t *testing.T) {
+	prod, shared := mergeTestInventories()
+
+	got := Merge(MergeInput{Name: "prod.json", Inventory: prod}, MergeInput{Name: "shared.json", Inventory: shared})
+
+	if !got.CollectedAt.Equal(shared.CollectedAt) {
+		t.Errorf("CollectedAt = %v, want newest %v", got.CollectedAt, shared.CollectedAt)
+	}
+	if got.Profile != "prod, shared" {
+		t.Errorf("Profile = %q, want %q", got.Profile, "prod, shared")
+	}
+	if len(got.Regions) != 2 || got.Regions[0] != "us-east-1" || got.Regions[1] != "eu-west-1" {
+		t.Errorf("Regions = %v, want [us-east-1 eu-west-1]", got.Regions)
+	}
+	if !got.Incomplete || got.CountsOnly {
+		t.Errorf("Incomplete, CountsOnly = %v, %v, want true, false", got.Incomplete, got.CountsOnly)
+	}
+
+	if len(got.Resources) != 3 {
+		t.Fatalf("Resources = %d, want 3", len(got.Resources))
+	}
+	wantSources := map[string]string{"logs": "shared.json", "vpc-1": "prod.json", "vpc-2": "shared.json"}
+	for _, r := range got.Resources {
+		if r.Source != wantSources[r.ResourceID] {
+			t.Errorf("%s Source = %q, want %q", r.ResourceID, r.Source, wantSources[r.ResourceID])
+		}
+	}
+	if got.Resources[0].ResourceName != "new" {
+		t.Errorf("duplicate resource name = %q, want the newer copy", got.Resources[0].ResourceName)
+	}
+
+	if len(got.Sources) != 2 {
+		t.Fatalf("Sources = %d, want 2", len(got.Sources))
+	}
+	s := got.Sources[1]
+	if s.Name != "shared.json" || s.Profile != "shared" || s.Resources != 2 || !s.Incomplete {
+		t.Errorf("Sources[1] = %+v", s)
+	}
+	if len(s.Accounts) != 2 || s.Accounts[0] != "111111111111" || s.Accounts[1] != "222222222222" {
+		t.Errorf("Sources[1].Accounts = %v, want both accounts sorted", s.Accounts)
+	}
+}
+
+func TestMerge_OlderInputDoesNotReplace(t *testing.T) {
+	prod, shared := mergeTestInventories()
+
+	got := Merge(MergeInput{Name: "shared.json", Inventory: shared}, MergeInput{Name: "prod.json", Inventory: prod})
+
+	for _, r := range got.Resources {
+		if r.ResourceID == "logs" && (r.ResourceName != "new" || r.Source != "shared.json") {
+			t.Errorf("logs = %+v, want the newer copy from shared.json", r)
+		}
+	}
+}
+
+func TestMerge_MergedInputUsesSourceTimes(t *testing.T) {
+	prod, shared := mergeTestInventories()
+	dev := NewInventory("dev", []Region{"us-west-2"})
+	dev.CollectedAt = time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC)
+	first := Merge(MergeInput{Name: "prod.json", Inventory: prod}, MergeInput{Name: "dev.json", Inventory: dev})
+
+	// first was collected on the 17th as a whole, but its copy of logs only
+	// on the 15th, so shared's copy from the 16th must win.
+	got := Merge(MergeInput{Name: "shared.json", Inventory: shared}, MergeInput{Name: "first.json", Inventory: first})
+
+	for _, r := range got.Resources {
+		if r.ResourceID == "logs" && (r.ResourceName != "new" || r.Source != "shared.json") {
+			t.Errorf("logs = %+v, want the newer copy from shared.json", r)
+		}
+	}
+}
+
+func TestMerge_MergedInputKeepsSources(t *testing.T) {
+	prod, shared := mergeTestInventories()
+	first := Merge(MergeInput{Name: "prod.json", Inventory: prod}, MergeInput{Name: "shared.json", Inventory: shared})
+
+	dev := NewInventory("dev", []Region{"us-west-2"})
+	dev.AddResource(Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-3", Region: "us-west-2"})
+	got := Merge(MergeInput{Name: "first.json", Inventory: first}, MergeInput{Inventory: dev})
+
+	if len(got.Sources) != 3 || got.Sources[2].Name != "dev" {
+		t.Fatalf("Sources = %+v, want prod.json, shared.json and dev", got.Sources)
+	}
+	for _, r := range got.Resources {
+		if r.Source == "first.json" {
+			t.Errorf("%s Source = first.json, want its original source", r.ResourceID)
+		}
+	}
+	if got.Profile != "prod, shared, dev" {
+		t.Errorf("Profile = %q, want %q", got.Profile, "prod, shared, dev")
+	}
+}
+
+func TestMerge_CountsOnly(t *testing.T) {
+	a := NewInventory("a", []Region{"us-east-1"})
+	a.CountsOnly = true
+	a.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", Count: 2})
+
+	if got := Merge(MergeInput{Inventory: a}, MergeInput{Inventory: a}); !got.CountsOnly || got.ResourceCount() != 4 {
+		t.Errorf("counts-only merge: CountsOnly = %v, ResourceCount = %d, want true, 4", got.CountsOnly, got.ResourceCount())
+	}
+}
+
+func TestMerge_CountsOnlyWithFullInventory(t *testing.T) {
+	census := NewInventory("census", []Region{"us-east-1"})
+	census.CountsOnly = true
+	census.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", Count: 5})
+	prod, shared := mergeTestInventories()
+	prod.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", Count: 99})
+
+	got := Merge(MergeInput{Inventory: census}, MergeInput{Inventory: prod}, MergeInput{Inventory: shared})
+
+	if !got.CountsOnly || len(got.Resources) != 0 {
+		t.Fatalf("CountsOnly = %v with %d resources, want a counts-only inventory", got.CountsOnly, len(got.Resources))
+	}
+	// 5 counted, plus logs (once), vpc-1 and vpc-2 from the full inventories.
+	if total := got.ResourceCount(); total != 8 {
+		t.Errorf("ResourceCount() = %d, want 8", total)
+	}
+	byType := got.ResourceCountByType()
+	if byType["AWS::EC2::VPC"] != 7 || byType["AWS::S3::Bucket"] != 1 {
+		t.Errorf("ResourceCountByType() = %v, want 7 VPCs and 1 bucket", byType)
+	}
+	if byRegion := got.ResourceCountByRegion(); byRegion["eu-west-1"] != 1 || byRegion["us-east-1"] != 7 {
+		t.Errorf("ResourceCountByRegion() = %v, want 7 in us-east-1 and 1 in eu-west-1", byRegion)
+	}
+}
+
+func TestReportGenerator_Generate_Sources(t *testing.T) {
+	prod, shared := mergeTestInventories()
+	inv := Merge(MergeInput{Name: "prod.json", Inventory: prod}, MergeInput{Name: "shared.json", Inventory: shared})
+
+	rg := NewReportGenerator(inv)
+	rg.IncludeDetails = true
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	want := `## Sources
+
+| Source | Profile | Accounts | Regions | Collected | Resources |
+|--------|---------|----------|---------|-----------|-----------|
+| prod.json | prod | 111111111111 | us-east-1 | 2024-01-15 10:00:00 UTC | 2 |
+| shared.json | shared | 111111111111, 222222222222 | eu-west-1, us-east-1 | 2024-01-16 10:00:00 UTC (incomplete) | 2 |
+
+## Summary
+`
+	if !strings.Contains(output, want) {
+		t.Errorf("Generate() sources section missing, got:\n%s", output)
+	}
+	if !strings.Contains(output, "| Name | ID | Region | ARN | Source |") ||
+		!strings.Contains(output, "| new | logs | us-east-1 | arn:aws:s3:::logs | shared.json |") {
+		t.Errorf("Generate() resource details should have a Source column, got:\n%s", output)
+	}
+}
+
+func TestReportGenerator_Generate_NoSourcesForUnmerged(t *testing.T) {
+	prod, _ := mergeTestInventories()
+	rg := NewReportGenerator(prod)
+	rg.IncludeDetails = true
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "## Sources") || strings.Contains(buf.String(), "Source |") {
+		t.Errorf("Generate() should not show sources for an unmerged inventory, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,197 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CollectionOutcome summarises collection runs for monitoring. The first
+// group of fields describes the most recent run; LastSuccess, Runs and
+// FailedRuns accumulate across runs.
+type CollectionOutcome struct {
+	StartedAt     time.Time
+	Duration      time.Duration
+	Success       bool
+	Resources     int
+	FailedRegions []Region
+	Throttles     int
+
+	LastSuccess time.Time
+	Runs        int
+	FailedRuns  int
+}
+
+// outcomeRecorder is an Observer that counts throttles during a run and
+// forwards every event to next. Regions are collected in parallel, so events
+// may arrive from several goroutines at once.
+type outcomeRecorder struct {
+	next Observer
+
+	mu        sync.Mutex
+	throttles int
+}
+
+func (o *outcomeRecorder) OnEvent(e Event) {
+	o.mu.Lock()
+	if e.Kind == EventThrottled {
+		o.throttles++
+	}
+	o.mu.Unlock()
+	if o.next != nil {
+		o.next.OnEvent(e)
+	}
+}
+
+const metricsPrefix = "aws_asset_inventory_"
+
+// WriteMetrics writes inventory gauges, and collection metrics when outcome
+// is not nil, in the Prometheus text exposition format. Either argument may
+// be nil.
+func WriteMetrics(w io.Writer, inv *Inventory, outcome *CollectionOutcome) error {
+	mw := &metricsWriter{w: w}
+
+	if inv != nil {
+		mw.header("resources", "gauge", "Resources in the inventory by type, region and account.")
+		for _, c := range countByTypeRegionAccount(inv) {
+			mw.sample("resources", float64(c.count),
+				"resource_type", c.resourceType.String(), "region", c.region.String(), "account", c.account)
+		}
+		mw.gauge("resources_total", "Resources in the inventory.", float64(inv.ResourceCount()))
+		mw.gauge("inventory_timestamp_seconds", "Time the inventory was collected.", unixSeconds(inv.CollectedAt))
+		mw.gauge("inventory_incomplete", "Whether the inventory is incomplete (1) or complete (0).", boolValue(inv.Incomplete))
+	}
+
+	if outcome != nil {
+		mw.gauge("collection_duration_seconds", "Duration of the most recent collection run.", outcome.Duration.Seconds())
+		mw.gauge("collection_success", "Whether the most recent collection run succeeded in every region.", boolValue(outcome.Success))
+		mw.gauge("collection_last_success_timestamp_seconds", "Time the most recent fully successful collection run finished.", unixSeconds(outcome.LastSuccess))
+		mw.gauge("collection_region_failures", "Regions that failed in the most recent collection run.", float64(len(outcome.FailedRegions)))
+		if len(outcome.FailedRegions) > 0 {
+			mw.header("collection_region_failed", "gauge", "Regions that failed in the most recent collection run.")
+			for _, r := range outcome.FailedRegions {
+				mw.sample("collection_region_failed", 1, "region", r.String())
+			}
+		}
+		mw.gauge("collection_throttles", "AWS Config calls throttled during the most recent collection run.", float64(outcome.Throttles))
+		mw.counter("collection_runs_total", "Collection runs since the process started.", float64(outcome.Runs))
+		mw.counter("collection_failed_runs_total", "Collection runs that failed in at least one region since the process started.", float64(outcome.FailedRuns))
+	}
+
+	return mw.err
+}
+
+type typeRegionAccountCount struct {
+	resourceType ResourceType
+	region       Region
+	account      string
+	count        int
+}
+
+// countByTypeRegionAccount counts resources by type, region and account,
+// sorted by those keys. Counts-only inventories have no account.
+func countByTypeRegionAccount(inv *Inventory) []typeRegionAccountCount {
+	type key struct {
+		resourceType ResourceType
+		region       Region
+		account      string
+	}
+	counts := make(map[key]int)
+	if inv.CountsOnly {
+		for _, c := range inv.Counts {
+			counts[key{c.ResourceType, c.Region, ""}] += c.Count
+		}
+	} else {
+		for _, r := range inv.Resources {
+			counts[key{r.ResourceType, r.Region, r.AccountID}]++
+		}
+	}
+
+	result := make([]typeRegionAccountCount, 0, len(counts))
+	for k, n := range counts {
+		result = append(result, typeRegionAccountCount{k.resourceType, k.region, k.account, n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.resourceType != b.resourceType {
+			return a.resourceType < b.resourceType
+		}
+		if a.region != b.region {
+			return a.region < b.region
+		}
+		return a.account < b.account
+	})
+	return result
+}
+
+// metricsWriter writes Prometheus text format, keeping the first error.
+type metricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (mw *metricsWriter) printf(format string, args ...any) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, args...)
+}
+
+func (mw *metricsWriter) header(name, kind, help string) {
+	mw.printf("# HELP %s%s %s\n", metricsPrefix, name, help)
+	mw.printf("# TYPE %s%s %s\n", metricsPrefix, name, kind)
+}
+
+// sample writes one sample; labels are alternating names and values.
+func (mw *metricsWriter) sample(name string, value float64, labels ...string) {
+	var sb strings.Builder
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, "%s=\"%s\"", labels[i], escapeLabelValue(labels[i+1]))
+	}
+	if sb.Len() > 0 {
+		mw.printf("%s%s{%s} %s\n", metricsPrefix, name, sb.String(), formatMetricValue(value))
+		return
+	}
+	mw.printf("%s%s %s\n", metricsPrefix, name, formatMetricValue(value))
+}
+
+func (mw *metricsWriter) gauge(name, help string, value float64) {
+	mw.header(name, "gauge", help)
+	mw.sample(name, value)
+}
+
+func (mw *metricsWriter) counter(name, help string, value float64) {
+	mw.header(name, "counter", help)
+	mw.sample(name, value)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func formatMetricValue(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+// unixSeconds returns t as fractional Unix seconds, or 0 for the zero time.
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics_Inventory(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	inv.CollectedAt = time.Unix(1700000000, 0).UTC()
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", AccountID: "111111111111"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", AccountID: "111111111111"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", AccountID: "222222222222"})
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, inv, nil); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"# TYPE aws_asset_inventory_resources gauge\n",
+		`aws_asset_inventory_resources{resource_type="AWS::EC2::Instance",region="us-east-1",account="111111111111"} 2` + "\n",
+		`aws_asset_inventory_resources{resource_type="AWS::EC2::Instance",region="us-east-1",account="222222222222"} 1` + "\n",
+		"aws_asset_inventory_resources_total 3\n",
+		"aws_asset_inventory_inventory_timestamp_seconds 1.7e+09\n",
+		"aws_asset_inventory_inventory_incomplete 0\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("metrics should contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "collection_") {
+		t.Error("collection metrics should be omitted without an outcome")
+	}
+}
+
+func TestWriteMetrics_CountsOnly(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	inv.CountsOnly = true
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Count: 7})
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, inv, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `aws_asset_inventory_resources{resource_type="AWS::S3::Bucket",region="us-east-1",account=""} 7`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("metrics should contain %q, got:\n%s", want, buf.String())
+	}
+}
+
+func TestWriteMetrics_Outcome(t *testing.T) {
+	outcome := &CollectionOutcome{
+		Duration:      90 * time.Second,
+		FailedRegions: []Region{"eu-west-1"},
+		Throttles:     4,
+		LastSuccess:   time.Unix(1700000000, 0),
+		Runs:          3,
+		FailedRuns:    1,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, nil, outcome); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"aws_asset_inventory_collection_duration_seconds 90\n",
+		"aws_asset_inventory_collection_success 0\n",
+		"aws_asset_inventory_collection_last_success_timestamp_seconds 1.7e+09\n",
+		"aws_asset_inventory_collection_region_failures 1\n",
+		`aws_asset_inventory_collection_region_failed{region="eu-west-1"} 1` + "\n",
+		"aws_asset_inventory_collection_throttles 4\n",
+		"# TYPE aws_asset_inventory_collection_runs_total counter\n",
+		"aws_asset_inventory_collection_runs_total 3\n",
+		"aws_asset_inventory_collection_failed_runs_total 1\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("metrics should contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "aws_asset_inventory_resources") {
+		t.Error("inventory metrics should be omitted without an inventory")
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	got := escapeLabelValue("a\"b\\c\nd")
+	if got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabelValue() = %s", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestWriteMetrics_WriteError(t *testing.T) {
+	if err := WriteMetrics(failingWriter{}, NewInventory("test", nil), nil); err == nil {
+		t.Error("WriteMetrics() should return the write error")
+	}
+}
@@ -0,0 +1,113 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// RenderOptions control what a Renderer includes in a report.
+type RenderOptions struct {
+	// IncludeDetails adds a listing of every resource to the report.
+	IncludeDetails bool
+
+	// GroupBy nests report sections by these keys, for renderers that
+	// support it; see ReportGenerator.GroupBy.
+	GroupBy []GroupKey
+
+	// GroupByTag adds a section per value of this tag key, for renderers
+	// that support it; see ReportGenerator.GroupByTag.
+	GroupByTag string
+
+	// DetailColumns, FullARNs and IncludeConfig extend the resource
+	// details, for renderers that support them; see ReportGenerator.
+	DetailColumns DetailColumns
+	FullARNs      bool
+	IncludeConfig bool
+}
+
+// Renderer writes a report of an inventory in one format. Renderers are
+// registered by format name with RegisterRenderer and selected with
+// ReportGenerator.Format.
+type Renderer interface {
+	Render(w io.Writer, inv *Inventory, opts RenderOptions) error
+}
+
+// RendererFunc adapts an ordinary function to a Renderer.
+type RendererFunc func(w io.Writer, inv *Inventory, opts RenderOptions) error
+
+// Render calls f(w, inv, opts).
+func (f RendererFunc) Render(w io.Writer, inv *Inventory, opts RenderOptions) error {
+	return f(w, inv, opts)
+}
+
+// Built-in report formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+	FormatText     = "text"
+)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{
+		FormatMarkdown: RendererFunc(renderMarkdown),
+		FormatHTML:     RendererFunc(renderHTML),
+		FormatJSON:     JSONRenderer{},
+		FormatText:     TextRenderer{},
+	}
+)
+
+// RegisterRenderer makes a report format available by name. It panics if
+// name is empty, r is nil or the name is already registered, so it is
+// typically called from an init function.
+func RegisterRenderer(name string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if name == "" || r == nil {
+		panic("awsassetinventory: RegisterRenderer needs a name and a renderer")
+	}
+	if _, dup := renderers[name]; dup {
+		panic(fmt.Sprintf("awsassetinventory: renderer %q already registered", name))
+	}
+	renderers[name] = r
+}
+
+// LookupRenderer returns the renderer registered for the format name.
+func LookupRenderer(name string) (Renderer, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	r, ok := renderers[name]
+	return r, ok
+}
+
+// RendererNames returns the registered format names in sorted order.
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func renderMarkdown(w io.Writer, inv *Inventory, opts RenderOptions) error {
+	rg := NewReportGenerator(inv)
+	rg.IncludeDetails = opts.IncludeDetails
+	rg.GroupBy = opts.GroupBy
+	rg.GroupByTag = opts.GroupByTag
+	rg.DetailColumns = opts.DetailColumns
+	rg.FullARNs = opts.FullARNs
+	rg.IncludeConfig = opts.IncludeConfig
+	return rg.generateMarkdown(w)
+}
+
+func renderHTML(w io.Writer, inv *Inventory, opts RenderOptions) error {
+	hg := NewHTMLReportGenerator(inv)
+	hg.IncludeDetails = opts.IncludeDetails
+	return hg.Generate(w)
+}
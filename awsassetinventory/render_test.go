@@ -0,0 +1,129 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRendererNames(t *testing.T) {
+	names := strings.Join(RendererNames(), ",")
+	for _, want := range []string{FormatHTML, FormatJSON, FormatMarkdown, FormatText} {
+		if _, ok := LookupRenderer(want); !ok {
+			t.Errorf("renderer %q should be registered", want)
+		}
+		if !strings.Contains(names, want) {
+			t.Errorf("RendererNames() = %s, missing %s", names, want)
+		}
+	}
+	if _, ok := LookupRenderer("pdf"); ok {
+		t.Error("LookupRenderer(pdf) should fail")
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	RegisterRenderer("test-count", RendererFunc(func(w io.Writer, inv *Inventory, opts RenderOptions) error {
+		_, err := io.WriteString(w, strings.Repeat("#", inv.ResourceCount()))
+		return err
+	}))
+	t.Cleanup(func() {
+		renderersMu.Lock()
+		delete(renderers, "test-count")
+		renderersMu.Unlock()
+	})
+
+	rg := NewReportGenerator(htmlTestInventory())
+	rg.Format = "test-count"
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if buf.String() != "####" {
+		t.Errorf("Generate() = %q, want the custom renderer's output", buf.String())
+	}
+
+	for _, tt := range []struct {
+		name     string
+		renderer Renderer
+	}{
+		{"test-count", TextRenderer{}},
+		{FormatMarkdown, TextRenderer{}},
+		{"", TextRenderer{}},
+		{"nil", nil},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterRenderer(%q) should panic", tt.name)
+				}
+			}()
+			RegisterRenderer(tt.name, tt.renderer)
+		}()
+	}
+}
+
+func TestReportGenerator_Generate_Format(t *testing.T) {
+	inv := htmlTestInventory()
+
+	var def, md bytes.Buffer
+	if err := NewReportGenerator(inv).Generate(&def); err != nil {
+		t.Fatal(err)
+	}
+	rg := NewReportGenerator(inv)
+	rg.Format = FormatMarkdown
+	if err := rg.Generate(&md); err != nil {
+		t.Fatal(err)
+	}
+	if def.String() != md.String() {
+		t.Error("the markdown format should match the default output")
+	}
+
+	rg.Format = "pdf"
+	if err := rg.Generate(&bytes.Buffer{}); err == nil {
+		t.Error("Generate() should fail for an unknown format")
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	inv := htmlTestInventory()
+	inv.Mismatches = []CountMismatch{{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Expected: 2, Collected: 1}}
+
+	var buf bytes.Buffer
+	if err := (JSONRenderer{}).Render(&buf, inv, RenderOptions{}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	var summary ReportSummary
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if summary.Total != 4 || summary.Profile != "prod" || !summary.Incomplete {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.ByType["AWS::EC2::Instance"] != 3 || summary.ByRegion["us-east-1"]["AWS::EC2::Instance"] != 2 {
+		t.Errorf("counts = %v %v", summary.ByType, summary.ByRegion)
+	}
+	if len(summary.Mismatches) != 1 {
+		t.Errorf("Mismatches = %v", summary.Mismatches)
+	}
+	if strings.Contains(buf.String(), `"resources"`) {
+		t.Error("resources should only be listed with details")
+	}
+
+	buf.Reset()
+	if err := (JSONRenderer{}).Render(&buf, inv, RenderOptions{IncludeDetails: true}); err != nil {
+		t.Fatal(err)
+	}
+	summary = ReportSummary{}
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range summary.Resources {
+		ids = append(ids, r.ResourceID)
+	}
+	if got := strings.Join(ids, ","); got != "i-1,i-3,i-2,logs" {
+		t.Errorf("resources = %s, want sorted by type, region and ID", got)
+	}
+}
@@ -7,10 +7,34 @@ import (
 	"strings"
 )
 
-// ReportGenerator generates markdown reports from inventory data.
+// ReportGenerator generates reports from inventory data, in markdown
+// unless another registered Format is chosen.
 type ReportGenerator struct {
 	inventory      *Inventory
 	IncludeDetails bool
+
+	// Format is the name of the registered Renderer to use; empty means
+	// markdown.
+	Format string
+
+	// GroupBy nests the counts by region section by these keys instead,
+	// outermost first. Grouping by account adds an account by type matrix,
+	// and grouping by az a spread of resources across each region's
+	// availability zones.
+	GroupBy []GroupKey
+
+	// GroupByTag adds a section with the counts and resources for every
+	// value of this tag key, and those without it.
+	GroupByTag string
+
+	// DetailColumns adds configuration or tag columns to the resource
+	// details, by resource type. FullARNs shows ARNs untruncated, and
+	// IncludeConfig adds each resource's configuration as a collapsible
+	// JSON block below its type's table. They only apply with
+	// IncludeDetails.
+	DetailColumns DetailColumns
+	FullARNs      bool
+	IncludeConfig bool
 }
 
 // NewReportGenerator creates a new ReportGenerator for the given inventory.
@@ -18,17 +42,69 @@ func NewReportGenerator(inv *Inventory) *ReportGenerator {
 	return &ReportGenerator{inventory: inv}
 }
 
-// Generate writes a complete markdown report to the provided writer.
+// Generate writes a complete report in rg.Format to the provided writer.
 func (rg *ReportGenerator) Generate(w io.Writer) error {
+	if rg.Format == "" || rg.Format == FormatMarkdown {
+		return rg.generateMarkdown(w)
+	}
+	r, ok := LookupRenderer(rg.Format)
+	if !ok {
+		return fmt.Errorf("unknown report format %q", rg.Format)
+	}
+	return r.Render(w, rg.inventory, RenderOptions{
+		IncludeDetails: rg.IncludeDetails,
+		GroupBy:        rg.GroupBy,
+		GroupByTag:     rg.GroupByTag,
+		DetailColumns:  rg.DetailColumns,
+		FullARNs:       rg.FullARNs,
+		IncludeConfig:  rg.IncludeConfig,
+	})
+}
+
+func (rg *ReportGenerator) generateMarkdown(w io.Writer) error {
 	if err := rg.writeHeader(w); err != nil {
 		return err
 	}
+	if rg.hasCollectionStatus() && rg.inventory.HasCollectionIssues() {
+		if err := rg.writeCollectionIssues(w); err != nil {
+			return err
+		}
+	}
+	if len(rg.inventory.Sources) > 0 {
+		if err := rg.writeSources(w); err != nil {
+			return err
+		}
+	}
 	if err := rg.writeSummary(w); err != nil {
 		return err
 	}
-	if err := rg.writeByRegion(w); err != nil {
+	if rg.hasGroupKey(GroupByAccount) {
+		if err := rg.writeByAccount(w); err != nil {
+			return err
+		}
+	}
+	if len(rg.GroupBy) > 0 {
+		if err := rg.writeGrouped(w); err != nil {
+			return err
+		}
+	} else if err := rg.writeByRegion(w); err != nil {
 		return err
 	}
+	if rg.hasGroupKey(GroupByAZ) {
+		if err := rg.writeAvailabilityZones(w); err != nil {
+			return err
+		}
+	}
+	if rg.GroupByTag != "" {
+		if err := rg.writeByTag(w); err != nil {
+			return err
+		}
+	}
+	if len(rg.inventory.Mismatches) > 0 {
+		if err := rg.writeReconciliation(w); err != nil {
+			return err
+		}
+	}
 	if rg.IncludeDetails {
 		if err := rg.writeResourceDetails(w); err != nil {
 			return err
@@ -59,10 +135,60 @@ func (rg *ReportGenerator) writeHeader(w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if rg.hasCollectionStatus() {
+		_, err = fmt.Fprintf(w, "**Completeness:** %s\n", completenessBadge(rg.inventory))
+		if err != nil {
+			return err
+		}
+	} else if rg.inventory.Incomplete {
+		_, err = fmt.Fprintf(w, "**Status:** Incomplete (collection failed in some regions or was interrupted)\n")
+		if err != nil {
+			return err
+		}
+	}
+	if rg.inventory.CountsOnly {
+		_, err = fmt.Fprintf(w, "**Mode:** Counts only\n")
+		if err != nil {
+			return err
+		}
+	}
 	_, err = fmt.Fprintf(w, "**Total Resources:** %d\n\n", rg.inventory.ResourceCount())
 	return err
 }
 
+// writeSources writes a table of the inventories a merged inventory was
+// built from.
+func (rg *ReportGenerator) writeSources(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## Sources\n\n")
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(rg.inventory.Sources))
+	for i, s := range rg.inventory.Sources {
+		regions := make([]string, len(s.Regions))
+		for j, r := range s.Regions {
+			regions[j] = r.String()
+		}
+		accounts := strings.Join(s.Accounts, ", ")
+		if accounts == "" {
+			accounts = "-"
+		}
+		collected := s.CollectedAt.Format("2006-01-02 15:04:05 UTC")
+		if s.Incomplete {
+			collected += " (incomplete)"
+		}
+		rows[i] = []string{
+			escapeMarkdown(s.Name),
+			escapeMarkdown(s.Profile),
+			accounts,
+			strings.Join(regions, ", "),
+			collected,
+			fmt.Sprint(s.Resources),
+		}
+	}
+	return writeMarkdownTable(w, []string{"Source", "Profile", "Accounts", "Regions", "Collected", "Resources"}, rows)
+}
+
 func (rg *ReportGenerator) writeSummary(w io.Writer) error {
 	_, err := fmt.Fprintf(w, "## Summary\n\n")
 	if err != nil {
@@ -142,12 +268,48 @@ func (rg *ReportGenerator) writeByRegion(w io.Writer) error {
 	return nil
 }
 
+func (rg *ReportGenerator) writeReconciliation(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## Reconciliation\n\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "Resource counts reported by AWS Config that differ from the resources collected:\n\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "| Region | Resource Type | Expected | Collected |\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|--------|---------------|----------|-----------|\n")
+	if err != nil {
+		return err
+	}
+
+	for _, m := range rg.inventory.Mismatches {
+		_, err = fmt.Fprintf(w, "| %s | %s | %d | %d |\n", m.Region, m.ResourceType, m.Expected, m.Collected)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
 func (rg *ReportGenerator) writeResourceDetails(w io.Writer) error {
 	_, err := fmt.Fprintf(w, "## Resource Details\n\n")
 	if err != nil {
 		return err
 	}
 
+	if rg.inventory.CountsOnly {
+		_, err = fmt.Fprintf(w, "Resource details are not available for counts-only inventories.\n\n")
+		return err
+	}
+
 	grouped := rg.inventory.ResourcesByType()
 	if len(grouped) == 0 {
 		_, err = fmt.Fprintf(w, "No resources to display.\n\n")
@@ -161,16 +323,30 @@ func (rg *ReportGenerator) writeResourceDetails(w io.Writer) error {
 		resources := grouped[rt]
 		sortResources(resources)
 
+		columns, err := rg.DetailColumns.forType(rt)
+		if err != nil {
+			return err
+		}
+		if len(rg.inventory.Sources) > 0 {
+			// Merged inventories show where each resource came from.
+			columns = append([]ExportColumn{sourceColumn}, columns...)
+		}
+		var extraHeader, extraSeparator string
+		for _, c := range columns {
+			extraHeader += fmt.Sprintf(" %s |", escapeMarkdown(c.Name))
+			extraSeparator += strings.Repeat("-", len(c.Name)+2) + "|"
+		}
+
 		_, err = fmt.Fprintf(w, "### %s (%d)\n\n", rt, len(resources))
 		if err != nil {
 			return err
 		}
 
-		_, err = fmt.Fprintf(w, "| Name | ID | Region | ARN |\n")
+		_, err = fmt.Fprintf(w, "| Name | ID | Region | ARN |%s\n", extraHeader)
 		if err != nil {
 			return err
 		}
-		_, err = fmt.Fprintf(w, "|------|----|----|-----|\n")
+		_, err = fmt.Fprintf(w, "|------|----|----|-----|%s\n", extraSeparator)
 		if err != nil {
 			return err
 		}
@@ -183,12 +359,23 @@ func (rg *ReportGenerator) writeResourceDetails(w io.Writer) error {
 			arn := r.ARN
 			if arn == "" {
 				arn = "-"
+			} else if !rg.FullARNs {
+				arn = truncateARN(arn)
 			}
-			_, err = fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			var extra string
+			for _, c := range columns {
+				value := c.Value(r)
+				if value == "" {
+					value = "-"
+				}
+				extra += fmt.Sprintf(" %s |", escapeMarkdown(value))
+			}
+			_, err = fmt.Fprintf(w, "| %s | %s | %s | %s |%s\n",
 				escapeMarkdown(name),
 				escapeMarkdown(r.ResourceID),
 				r.Region,
-				escapeMarkdown(truncateARN(arn)))
+				escapeMarkdown(arn),
+				extra)
 			if err != nil {
 				return err
 			}
@@ -198,11 +385,19 @@ func (rg *ReportGenerator) writeResourceDetails(w io.Writer) error {
 		if err != nil {
 			return err
 		}
+
+		if rg.IncludeConfig {
+			if err := writeResourceConfig(w, resources); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+var sourceColumn = ExportColumn{Name: "Source", field: fieldOperand{field: "source"}}
+
 func sortedResourceTypes(counts map[ResourceType]int) []ResourceType {
 	types := make([]ResourceType, 0, len(counts))
 	for rt := range counts {
@@ -234,6 +429,33 @@ func sortResources(resources []Resource) {
 	})
 }
 
+// writeMarkdownTable writes a markdown table with the given header and
+// rows, followed by a blank line. Cells are written as they are.
+func writeMarkdownTable(w io.Writer, header []string, rows [][]string) error {
+	_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	if err != nil {
+		return err
+	}
+	separators := make([]string, len(header))
+	for i, h := range header {
+		separators[i] = strings.Repeat("-", len(h)+2)
+	}
+	_, err = fmt.Fprintf(w, "|%s|\n", strings.Join(separators, "|"))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		_, err = fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
 func escapeMarkdown(s string) string {
 	s = strings.ReplaceAll(s, "|", "\\|")
 	s = strings.ReplaceAll(s, "\n", " ")
@@ -359,3 +359,92 @@ func TestReportGenerator_Generate_IncludeDetails(t *testing.T) {
 		t.Error("Generate() with IncludeDetails should include individual resource names")
 	}
 }
+
+func TestReportGenerator_Generate_CountsOnly(t *testing.T) {
+	inv := &Inventory{
+		CollectedAt: time.Date(2026, 1, 7, 15, 30, 0, 0, time.UTC),
+		Profile:     "test",
+		Regions:     []Region{"us-east-1"},
+		CountsOnly:  true,
+		Counts: []ResourceTypeCount{
+			{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Count: 12},
+		},
+		Resources: []Resource{},
+	}
+	rg := NewReportGenerator(inv)
+	rg.IncludeDetails = true
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "**Mode:** Counts only") {
+		t.Error("Generate() should flag counts-only inventories in the header")
+	}
+	if !strings.Contains(output, "**Total Resources:** 12") {
+		t.Error("Generate() should total counts-only inventories from counts")
+	}
+	if !strings.Contains(output, "| AWS::EC2::Instance | 12 |") {
+		t.Error("Generate() should render counts in summary and by-region tables")
+	}
+	if !strings.Contains(output, "not available for counts-only inventories") {
+		t.Error("Generate() should explain missing details for counts-only inventories")
+	}
+}
+
+func TestReportGenerator_Generate_Reconciliation(t *testing.T) {
+	inv := &Inventory{
+		CollectedAt: time.Date(2026, 1, 7, 15, 30, 0, 0, time.UTC),
+		Profile:     "test",
+		Regions:     []Region{"us-east-1"},
+		Mismatches: []CountMismatch{
+			{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Expected: 5, Collected: 3},
+		},
+		Resources: []Resource{},
+	}
+	rg := NewReportGenerator(inv)
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "## Reconciliation") {
+		t.Error("Generate() should include reconciliation section when mismatches exist")
+	}
+	if !strings.Contains(output, "| us-east-1 | AWS::EC2::Instance | 5 | 3 |") {
+		t.Error("Generate() should list expected and collected counts")
+	}
+}
+
+func TestReportGenerator_Generate_NoReconciliationWhenComplete(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	rg := NewReportGenerator(inv)
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "## Reconciliation") {
+		t.Error("Generate() should omit reconciliation section when counts match")
+	}
+}
+
+func TestReportGenerator_Generate_Incomplete(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	inv.Incomplete = true
+	rg := NewReportGenerator(inv)
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "**Status:** Incomplete") {
+		t.Error("Generate() should flag incomplete inventories in the header")
+	}
+}
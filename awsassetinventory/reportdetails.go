@@ -0,0 +1,122 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+)
+
+// DetailColumn is an extra column of the report's resource details: a
+// heading and the field it shows, named as for export columns, such as
+// config.instanceType or tags.owner.
+type DetailColumn struct {
+	Name  string `json:"name"`
+	Field string `json:"field"`
+}
+
+// UnmarshalJSON accepts either an object with name and field, or a plain
+// field string that is also used as the heading.
+func (c *DetailColumn) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*c = DetailColumn{Name: field, Field: field}
+		return nil
+	}
+	type plain DetailColumn
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.Name == "" {
+		p.Name = p.Field
+	}
+	*c = DetailColumn(p)
+	return nil
+}
+
+// DetailColumns maps a resource type, or AllResourceTypes, to the extra
+// columns shown for its resources. Resources get the AllResourceTypes
+// columns followed by those for their own type. Column files use the same
+// shape:
+//
+//	{
+//	  "*": ["tags.owner"],
+//	  "AWS::EC2::Instance": [{"name": "Instance Type", "field": "config.instanceType"}],
+//	  "AWS::RDS::DBInstance": [{"name": "Engine", "field": "config.engineVersion"}],
+//	  "AWS::EC2::VPC": [{"name": "CIDR", "field": "config.cidrBlock"}]
+//	}
+type DetailColumns map[string][]DetailColumn
+
+// LoadDetailColumns reads a column mapping from a JSON file and checks that
+// every field is valid.
+func LoadDetailColumns(path string) (DetailColumns, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var columns DetailColumns
+	if err := json.Unmarshal(data, &columns); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for rt, cols := range columns {
+		for _, c := range cols {
+			if _, err := parseExportColumn(c.Field); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, rt, err)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// forType returns the parsed columns for resources of rt.
+func (dc DetailColumns) forType(rt ResourceType) ([]ExportColumn, error) {
+	var columns []ExportColumn
+	for _, key := range []string{AllResourceTypes, rt.String()} {
+		for _, c := range dc[key] {
+			col, err := parseExportColumn(c.Field)
+			if err != nil {
+				return nil, err
+			}
+			col.Name = c.Name
+			columns = append(columns, col)
+		}
+	}
+	return columns, nil
+}
+
+// writeResourceConfig writes each resource's configuration as a
+// collapsible JSON block.
+func writeResourceConfig(w io.Writer, resources []Resource) error {
+	for _, r := range resources {
+		if len(r.Configuration) == 0 {
+			continue
+		}
+		summary := r.ResourceID
+		if r.ResourceName != "" && r.ResourceName != r.ResourceID {
+			summary = fmt.Sprintf("%s (%s)", r.ResourceID, r.ResourceName)
+		}
+
+		var config bytes.Buffer
+		if err := json.Indent(&config, r.Configuration, "", "  "); err != nil {
+			config.Reset()
+			config.Write(r.Configuration)
+		}
+
+		_, err := fmt.Fprintf(w, "<details><summary>%s</summary>\n\n", html.EscapeString(summary))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "```json\n%s\n```\n\n", config.String())
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "</details>\n\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
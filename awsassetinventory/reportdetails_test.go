@@ -0,0 +1,80 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDetailColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "columns.json")
+	data := `{"*": ["tags.owner"], "AWS::EC2::Instance": [{"name": "Instance Type", "field": "config.instanceType"}, {"field": "config.state.name"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := LoadDetailColumns(path)
+	if err != nil {
+		t.Fatalf("LoadDetailColumns() error = %v", err)
+	}
+	want := []DetailColumn{{Name: "Instance Type", Field: "config.instanceType"}, {Name: "config.state.name", Field: "config.state.name"}}
+	if got := columns["AWS::EC2::Instance"]; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("EC2 columns = %+v, want %+v", got, want)
+	}
+	if got := columns["*"]; len(got) != 1 || got[0] != (DetailColumn{Name: "tags.owner", Field: "tags.owner"}) {
+		t.Errorf("* columns = %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"AWS::EC2::Instance": ["config..bad"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDetailColumns(path); err == nil {
+		t.Error("LoadDetailColumns() should reject an invalid field")
+	}
+}
+
+func TestReportGenerator_Generate_DetailColumns(t *testing.T) {
+	inv := &Inventory{
+		Profile: "prod",
+		Regions: []Region{"us-east-1"},
+		Resources: []Resource{
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1",
+				ARN:           "arn:aws:ec2:us-east-1:123456789012:instance/i-1-with-a-rather-long-identifier",
+				Configuration: json.RawMessage(`{"instanceType":"m5.large","state":{"name":"running"}}`),
+				Tags:          map[string]string{"owner": "web-team"}},
+			{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"},
+		},
+	}
+
+	rg := NewReportGenerator(inv)
+	rg.IncludeDetails = true
+	rg.FullARNs = true
+	rg.IncludeConfig = true
+	rg.DetailColumns = DetailColumns{
+		AllResourceTypes:     {{Name: "Owner", Field: "tags.owner"}},
+		"AWS::EC2::Instance": {{Name: "Instance Type", Field: "config.instanceType"}},
+	}
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"| Name | ID | Region | ARN | Owner | Instance Type |\n|------|----|----|-----|-------|---------------|\n" +
+			"| web | i-1 | us-east-1 | arn:aws:ec2:us-east-1:123456789012:instance/i-1-with-a-rather-long-identifier | web-team | m5.large |\n",
+		"| Name | ID | Region | ARN | Owner |\n|------|----|----|-----|-------|\n| - | logs | us-east-1 | - | - |\n",
+		"<details><summary>i-1 (web)</summary>\n\n```json\n{\n  \"instanceType\": \"m5.large\",\n  \"state\": {\n    \"name\": \"running\"\n  }\n}\n```\n\n</details>\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report should contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "<details>") != 1 {
+		t.Error("resources without configuration should have no JSON block")
+	}
+}
@@ -0,0 +1,391 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GroupKey is a resource dimension that report sections can be grouped by.
+type GroupKey string
+
+// Report grouping dimensions.
+const (
+	GroupByAccount GroupKey = "account"
+	GroupByRegion  GroupKey = "region"
+	GroupByAZ      GroupKey = "az"
+	GroupByType    GroupKey = "type"
+)
+
+var groupKeyLabels = map[GroupKey]string{
+	GroupByAccount: "Account",
+	GroupByRegion:  "Region",
+	GroupByAZ:      "Availability Zone",
+	GroupByType:    "Resource Type",
+}
+
+// ParseGroupBy parses a comma-separated list of group keys, such as
+// "account,region,type", outermost first.
+func ParseGroupBy(s string) ([]GroupKey, error) {
+	var keys []GroupKey
+	seen := make(map[GroupKey]bool)
+	for _, part := range strings.Split(s, ",") {
+		key := GroupKey(strings.TrimSpace(part))
+		if _, ok := groupKeyLabels[key]; !ok {
+			return nil, fmt.Errorf("invalid group key %q: must be account, region, az or type", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("group key %q is repeated", key)
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// value returns r's value for the key, with a placeholder when it has none.
+func (k GroupKey) value(r Resource) string {
+	switch k {
+	case GroupByAccount:
+		if r.AccountID == "" {
+			return "(unknown)"
+		}
+		return r.AccountID
+	case GroupByRegion:
+		return r.Region.String()
+	case GroupByAZ:
+		az := zonalAvailabilityZone(r.AvailabilityZone)
+		if az == "" {
+			return "(regional)"
+		}
+		return az
+	default:
+		return r.ResourceType.String()
+	}
+}
+
+// countedResource is a resource standing for count resources; counts-only
+// inventories are grouped as one per type and region.
+type countedResource struct {
+	resource Resource
+	count    int
+}
+
+func (rg *ReportGenerator) countedResources() []countedResource {
+	if rg.inventory.CountsOnly {
+		items := make([]countedResource, len(rg.inventory.Counts))
+		for i, c := range rg.inventory.Counts {
+			items[i] = countedResource{resource: Resource{ResourceType: c.ResourceType, Region: c.Region}, count: c.Count}
+		}
+		return items
+	}
+	items := make([]countedResource, len(rg.inventory.Resources))
+	for i, r := range rg.inventory.Resources {
+		items[i] = countedResource{resource: r, count: 1}
+	}
+	return items
+}
+
+func (rg *ReportGenerator) hasGroupKey(key GroupKey) bool {
+	for _, k := range rg.GroupBy {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGrouped writes resource counts nested by rg.GroupBy: a heading for
+// each value of every key but the last, and a table of counts by the last.
+func (rg *ReportGenerator) writeGrouped(w io.Writer) error {
+	labels := make([]string, len(rg.GroupBy))
+	for i, k := range rg.GroupBy {
+		labels[i] = groupKeyLabels[k]
+	}
+	_, err := fmt.Fprintf(w, "## By %s\n\n", strings.Join(labels, " > "))
+	if err != nil {
+		return err
+	}
+
+	items := rg.countedResources()
+	if len(items) == 0 {
+		_, err = fmt.Fprintf(w, "No resources found.\n\n")
+		return err
+	}
+	return rg.writeGroupLevel(w, items, 0)
+}
+
+func (rg *ReportGenerator) writeGroupLevel(w io.Writer, items []countedResource, depth int) error {
+	key := rg.GroupBy[depth]
+	groups := make(map[string][]countedResource)
+	totals := make(map[string]int)
+	for _, item := range items {
+		v := key.value(item.resource)
+		groups[v] = append(groups[v], item)
+		totals[v] += item.count
+	}
+	values := make([]string, 0, len(groups))
+	for v := range groups {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	if depth == len(rg.GroupBy)-1 {
+		rows := make([][]string, len(values))
+		for i, v := range values {
+			rows[i] = []string{escapeMarkdown(v), fmt.Sprint(totals[v])}
+		}
+		return writeMarkdownTable(w, []string{groupKeyLabels[key], "Count"}, rows)
+	}
+
+	for _, v := range values {
+		_, err := fmt.Fprintf(w, "%s %s (%d)\n\n", strings.Repeat("#", depth+3), escapeMarkdown(v), totals[v])
+		if err != nil {
+			return err
+		}
+		if err := rg.writeGroupLevel(w, groups[v], depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeByAccount writes a matrix of resource counts with a row per
+// resource type and a column per account.
+func (rg *ReportGenerator) writeByAccount(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## By Account\n\n")
+	if err != nil {
+		return err
+	}
+	if rg.inventory.CountsOnly {
+		_, err = fmt.Fprintf(w, "Account counts are not available for counts-only inventories.\n\n")
+		return err
+	}
+
+	countsByAccount := rg.inventory.ResourceCountByTypeAndAccount()
+	if len(countsByAccount) == 0 {
+		_, err = fmt.Fprintf(w, "No resources found.\n\n")
+		return err
+	}
+	accounts := make([]string, 0, len(countsByAccount))
+	for a := range countsByAccount {
+		accounts = append(accounts, a)
+	}
+	sort.Strings(accounts)
+
+	header := []string{"Resource Type"}
+	for _, a := range accounts {
+		header = append(header, escapeMarkdown(GroupByAccount.value(Resource{AccountID: a})))
+	}
+	header = append(header, "Total")
+
+	accountTotals := rg.inventory.ResourceCountByAccount()
+	typeCounts := rg.inventory.ResourceCountByType()
+	var rows [][]string
+	for _, rt := range sortedResourceTypes(typeCounts) {
+		row := []string{rt.String()}
+		for _, a := range accounts {
+			row = append(row, fmt.Sprint(countsByAccount[a][rt]))
+		}
+		rows = append(rows, append(row, fmt.Sprint(typeCounts[rt])))
+	}
+	total := []string{"**Total**"}
+	for _, a := range accounts {
+		total = append(total, fmt.Sprint(accountTotals[a]))
+	}
+	rows = append(rows, append(total, fmt.Sprint(rg.inventory.ResourceCount())))
+
+	return writeMarkdownTable(w, header, rows)
+}
+
+// writeAvailabilityZones writes, for every region, a matrix of zonal
+// resources with a row per resource type and a column per availability
+// zone, so uneven spreads stand out.
+func (rg *ReportGenerator) writeAvailabilityZones(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## By Availability Zone\n\n")
+	if err != nil {
+		return err
+	}
+	if rg.inventory.CountsOnly {
+		_, err = fmt.Fprintf(w, "Availability zone counts are not available for counts-only inventories.\n\n")
+		return err
+	}
+
+	zoneTotals := rg.inventory.ResourceCountByAvailabilityZone()
+	if len(zoneTotals) == 0 {
+		_, err = fmt.Fprintf(w, "No resources are placed in an availability zone.\n\n")
+		return err
+	}
+
+	counts := make(map[Region]map[ResourceType]map[string]int)
+	for _, r := range rg.inventory.Resources {
+		az := zonalAvailabilityZone(r.AvailabilityZone)
+		if az == "" {
+			continue
+		}
+		if counts[r.Region] == nil {
+			counts[r.Region] = make(map[ResourceType]map[string]int)
+		}
+		if counts[r.Region][r.ResourceType] == nil {
+			counts[r.Region][r.ResourceType] = make(map[string]int)
+		}
+		counts[r.Region][r.ResourceType][az]++
+	}
+
+	regions := make([]Region, 0, len(zoneTotals))
+	for region := range zoneTotals {
+		regions = append(regions, region)
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i] < regions[j] })
+
+	for _, region := range regions {
+		_, err = fmt.Fprintf(w, "### %s\n\n", region)
+		if err != nil {
+			return err
+		}
+
+		zones := make([]string, 0, len(zoneTotals[region]))
+		for z := range zoneTotals[region] {
+			zones = append(zones, z)
+		}
+		sort.Strings(zones)
+		header := append([]string{"Resource Type"}, zones...)
+		header = append(header, "Total")
+
+		typeCounts := make(map[ResourceType]int)
+		for rt, zoneCounts := range counts[region] {
+			for _, n := range zoneCounts {
+				typeCounts[rt] += n
+			}
+		}
+		var rows [][]string
+		regionTotal := 0
+		for _, rt := range sortedResourceTypes(typeCounts) {
+			row := []string{rt.String()}
+			for _, z := range zones {
+				row = append(row, fmt.Sprint(counts[region][rt][z]))
+			}
+			rows = append(rows, append(row, fmt.Sprint(typeCounts[rt])))
+			regionTotal += typeCounts[rt]
+		}
+		total := []string{"**Total**"}
+		for _, z := range zones {
+			total = append(total, fmt.Sprint(zoneTotals[region][z]))
+		}
+		rows = append(rows, append(total, fmt.Sprint(regionTotal)))
+
+		if err := writeMarkdownTable(w, header, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeByTag writes, for every value of the tag rg.GroupByTag, the counts
+// by resource type and a table of its resources. Resources without the tag
+// are listed last as untagged, unless no resource has any tags.
+func (rg *ReportGenerator) writeByTag(w io.Writer) error {
+	key := rg.GroupByTag
+	_, err := fmt.Fprintf(w, "## By Tag: %s\n\n", escapeMarkdown(key))
+	if err != nil {
+		return err
+	}
+	if rg.inventory.CountsOnly {
+		_, err = fmt.Fprintf(w, "Tags are not available for counts-only inventories.\n\n")
+		return err
+	}
+	if len(rg.inventory.Resources) == 0 {
+		_, err = fmt.Fprintf(w, "No resources found.\n\n")
+		return err
+	}
+	if !rg.inventory.HasTags() {
+		// Listing every resource as untagged would hide that the tags are
+		// missing from the inventory rather than from the resources.
+		_, err = fmt.Fprintf(w, "No resource in the inventory has tags. It may have been collected without permission to read them (`config:SelectResourceConfig`).\n\n")
+		return err
+	}
+
+	groups := make(map[string][]Resource)
+	var untagged []Resource
+	for _, r := range rg.inventory.Resources {
+		value, ok := r.Tags[key]
+		if !ok {
+			untagged = append(untagged, r)
+			continue
+		}
+		groups[value] = append(groups[value], r)
+	}
+	values := make([]string, 0, len(groups))
+	for v := range groups {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	rows := make([][]string, 0, len(values)+1)
+	for _, v := range values {
+		rows = append(rows, []string{escapeMarkdown(tagValueLabel(v)), fmt.Sprint(len(groups[v]))})
+	}
+	if len(untagged) > 0 {
+		rows = append(rows, []string{"Untagged", fmt.Sprint(len(untagged))})
+	}
+	if err := writeMarkdownTable(w, []string{"Value", "Count"}, rows); err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		heading := fmt.Sprintf("%s = %s", escapeMarkdown(key), escapeMarkdown(tagValueLabel(v)))
+		if err := writeTagGroup(w, heading, groups[v]); err != nil {
+			return err
+		}
+	}
+	if len(untagged) > 0 {
+		return writeTagGroup(w, "Untagged", untagged)
+	}
+	return nil
+}
+
+func tagValueLabel(v string) string {
+	if v == "" {
+		return "(empty)"
+	}
+	return v
+}
+
+// writeTagGroup writes the counts by type and the resources of one tag
+// value under heading.
+func writeTagGroup(w io.Writer, heading string, resources []Resource) error {
+	_, err := fmt.Fprintf(w, "### %s (%d)\n\n", heading, len(resources))
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[ResourceType]int)
+	grouped := make(map[ResourceType][]Resource)
+	for _, r := range resources {
+		counts[r.ResourceType]++
+		grouped[r.ResourceType] = append(grouped[r.ResourceType], r)
+	}
+	types := sortedResourceTypes(counts)
+
+	summary := make([][]string, len(types))
+	for i, rt := range types {
+		summary[i] = []string{rt.String(), fmt.Sprint(counts[rt])}
+	}
+	if err := writeMarkdownTable(w, []string{"Resource Type", "Count"}, summary); err != nil {
+		return err
+	}
+
+	var details [][]string
+	for _, rt := range types {
+		sortResources(grouped[rt])
+		for _, r := range grouped[rt] {
+			name := r.ResourceName
+			if name == "" {
+				name = "-"
+			}
+			details = append(details, []string{rt.String(), escapeMarkdown(name), escapeMarkdown(r.ResourceID), r.Region.String()})
+		}
+	}
+	return writeMarkdownTable(w, []string{"Resource Type", "Name", "ID", "Region"}, details)
+}
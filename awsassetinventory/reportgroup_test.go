@@ -0,0 +1,269 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func groupTestInventory() *Inventory {
+	return &Inventory{
+		Profile: "prod",
+		Regions: []Region{"us-east-1", "us-west-2"},
+		Resources: []Resource{
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1", AvailabilityZone: "us-east-1a", AccountID: "111111111111"},
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-east-1", AvailabilityZone: "us-east-1a", AccountID: "111111111111"},
+			{ResourceType: "AWS::EC2::Instance", ResourceID: "i-3", Region: "us-east-1", AvailabilityZone: "us-east-1b", AccountID: "222222222222"},
+			{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-west-2", AccountID: "222222222222"},
+		},
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	keys, err := ParseGroupBy("account, region,az,type")
+	if err != nil {
+		t.Fatalf("ParseGroupBy() error = %v", err)
+	}
+	if len(keys) != 4 || keys[0] != GroupByAccount || keys[2] != GroupByAZ {
+		t.Errorf("ParseGroupBy() = %v", keys)
+	}
+
+	for _, s := range []string{"", "owner", "region,region"} {
+		if _, err := ParseGroupBy(s); err == nil {
+			t.Errorf("ParseGroupBy(%q) should fail", s)
+		}
+	}
+}
+
+func TestReportGenerator_Generate_GroupBy(t *testing.T) {
+	rg := NewReportGenerator(groupTestInventory())
+	rg.GroupBy = []GroupKey{GroupByAccount, GroupByRegion, GroupByType}
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	wantAccounts := `## By Account
+
+| Resource Type | 111111111111 | 222222222222 | Total |
+|---------------|--------------|--------------|-------|
+| AWS::EC2::Instance | 2 | 1 | 3 |
+| AWS::S3::Bucket | 0 | 1 | 1 |
+| **Total** | 2 | 2 | 4 |
+`
+	wantGrouped := `## By Account > Region > Resource Type
+
+### 111111111111 (2)
+
+#### us-east-1 (2)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::EC2::Instance | 2 |
+
+### 222222222222 (2)
+
+#### us-east-1 (1)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::EC2::Instance | 1 |
+
+#### us-west-2 (1)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::S3::Bucket | 1 |
+`
+	for _, want := range []string{wantAccounts, wantGrouped} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report should contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "## By Region\n") || strings.Contains(output, "## By Availability Zone") {
+		t.Error("grouped report should replace By Region and only add sections for its keys")
+	}
+}
+
+func TestReportGenerator_Generate_GroupByAZ(t *testing.T) {
+	rg := NewReportGenerator(groupTestInventory())
+	rg.GroupBy = []GroupKey{GroupByRegion, GroupByAZ}
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"### us-west-2 (1)\n\n| Availability Zone | Count |\n|-------------------|-------|\n| (regional) | 1 |\n",
+		`## By Availability Zone
+
+### us-east-1
+
+| Resource Type | us-east-1a | us-east-1b | Total |
+|---------------|------------|------------|-------|
+| AWS::EC2::Instance | 2 | 1 | 3 |
+| **Total** | 2 | 1 | 3 |
+`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report should contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "### us-west-2\n") {
+		t.Error("regions without zonal resources should not have an availability zone table")
+	}
+}
+
+func TestReportGenerator_Generate_GroupByAZ_NonZonal(t *testing.T) {
+	inv := groupTestInventory()
+	inv.Resources = append(inv.Resources,
+		Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-1", Region: "us-east-1", AvailabilityZone: "Regional"},
+		Resource{ResourceType: "AWS::IAM::Role", ResourceID: "role-1", Region: "us-east-1", AvailabilityZone: "Not Applicable"},
+		Resource{ResourceType: "AWS::RDS::DBInstance", ResourceID: "db-1", Region: "us-east-1", AvailabilityZone: "Multiple Availability Zones"},
+	)
+	rg := NewReportGenerator(inv)
+	rg.GroupBy = []GroupKey{GroupByRegion, GroupByAZ}
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"| (regional) | 3 |\n| us-east-1a | 2 |\n| us-east-1b | 1 |\n",
+		"| Resource Type | us-east-1a | us-east-1b | Total |\n",
+		"| **Total** | 2 | 1 | 3 |\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report should contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+	for _, sentinel := range []string{"Regional", "Not Applicable", "Multiple Availability Zones"} {
+		if strings.Contains(output, sentinel) {
+			t.Errorf("report should not show %q as an availability zone, got:\n%s", sentinel, output)
+		}
+	}
+}
+
+func TestReportGenerator_Generate_GroupByCountsOnly(t *testing.T) {
+	rg := NewReportGenerator(&Inventory{
+		CountsOnly: true,
+		Counts:     []ResourceTypeCount{{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Count: 7}},
+	})
+	rg.GroupBy = []GroupKey{GroupByAccount, GroupByType}
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		"Account counts are not available for counts-only inventories.",
+		"### (unknown) (7)\n\n| Resource Type | Count |\n|---------------|-------|\n| AWS::S3::Bucket | 7 |\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report should contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestReportGenerator_Generate_GroupByTag(t *testing.T) {
+	inv := groupTestInventory()
+	inv.Resources[0].Tags = map[string]string{"team": "web"}
+	inv.Resources[0].ResourceName = "frontend"
+	inv.Resources[2].Tags = map[string]string{"team": "data"}
+	inv.Resources[3].Tags = map[string]string{"team": "web"}
+
+	rg := NewReportGenerator(inv)
+	rg.GroupByTag = "team"
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `## By Tag: team
+
+| Value | Count |
+|-------|-------|
+| data | 1 |
+| web | 2 |
+| Untagged | 1 |
+
+### team = data (1)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::EC2::Instance | 1 |
+
+| Resource Type | Name | ID | Region |
+|---------------|------|----|--------|
+| AWS::EC2::Instance | - | i-3 | us-east-1 |
+
+### team = web (2)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::EC2::Instance | 1 |
+| AWS::S3::Bucket | 1 |
+
+| Resource Type | Name | ID | Region |
+|---------------|------|----|--------|
+| AWS::EC2::Instance | frontend | i-1 | us-east-1 |
+| AWS::S3::Bucket | - | logs | us-west-2 |
+
+### Untagged (1)
+
+| Resource Type | Count |
+|---------------|-------|
+| AWS::EC2::Instance | 1 |
+
+| Resource Type | Name | ID | Region |
+|---------------|------|----|--------|
+| AWS::EC2::Instance | - | i-2 | us-east-1 |
+
+`
+	output := buf.String()
+	if !strings.HasSuffix(output, want) {
+		t.Errorf("report should end with:\n%s\ngot:\n%s", want, output)
+	}
+	if !strings.Contains(output, "## By Region\n") {
+		t.Error("grouping by tag should keep the By Region section")
+	}
+}
+
+func TestReportGenerator_Generate_GroupByTagCountsOnly(t *testing.T) {
+	rg := NewReportGenerator(&Inventory{CountsOnly: true})
+	rg.GroupByTag = "team"
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Tags are not available for counts-only inventories.") {
+		t.Errorf("report = %s", buf.String())
+	}
+}
+
+func TestReportGenerator_Generate_GroupByTagNoTags(t *testing.T) {
+	inv := NewInventory("prod", []Region{"us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+	rg := NewReportGenerator(inv)
+	rg.GroupByTag = "team"
+
+	var buf bytes.Buffer
+	if err := rg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "No resource in the inventory has tags.") {
+		t.Errorf("report should explain that the inventory has no tags:\n%s", out)
+	}
+	if strings.Contains(out, "Untagged") {
+		t.Errorf("report should not list every resource as untagged:\n%s", out)
+	}
+}
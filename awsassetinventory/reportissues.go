@@ -0,0 +1,86 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hasCollectionStatus reports whether the inventory records region statuses
+// or resource failures; older inventories record neither.
+func (rg *ReportGenerator) hasCollectionStatus() bool {
+	return len(rg.inventory.RegionStatus) > 0 || len(rg.inventory.Failures) > 0
+}
+
+// completenessBadge summarises how complete the collection was, for example
+// "INCOMPLETE (2 of 3 regions collected, 4 resources without configuration)".
+func completenessBadge(inv *Inventory) string {
+	state := "COMPLETE"
+	if inv.HasCollectionIssues() {
+		state = "INCOMPLETE"
+	}
+
+	var details []string
+	if len(inv.RegionStatus) > 0 {
+		collected := len(inv.RegionStatus) - len(inv.FailedRegions())
+		details = append(details, fmt.Sprintf("%d of %d regions collected", collected, len(inv.RegionStatus)))
+	}
+	switch len(inv.Failures) {
+	case 0:
+	case 1:
+		details = append(details, "1 resource without configuration")
+	default:
+		details = append(details, fmt.Sprintf("%d resources without configuration", len(inv.Failures)))
+	}
+	if len(details) == 0 {
+		return state
+	}
+	return fmt.Sprintf("%s (%s)", state, strings.Join(details, ", "))
+}
+
+// writeCollectionIssues writes the regions that failed and the resources
+// whose configuration could not be retrieved, ahead of the counts they
+// affect.
+func (rg *ReportGenerator) writeCollectionIssues(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## Collection Issues\n\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "> **Warning:** This inventory is incomplete. The counts and resources in this report leave out what could not be collected.\n\n")
+	if err != nil {
+		return err
+	}
+
+	if len(rg.inventory.FailedRegions()) > 0 {
+		_, err = fmt.Fprintf(w, "### Regions\n\n")
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(rg.inventory.RegionStatus))
+		for i, s := range rg.inventory.RegionStatus {
+			errText := s.Error
+			if errText == "" {
+				errText = "-"
+			}
+			rows[i] = []string{s.Region.String(), s.Status, fmt.Sprint(s.Resources), escapeMarkdown(errText)}
+		}
+		if err := writeMarkdownTable(w, []string{"Region", "Status", "Resources", "Error"}, rows); err != nil {
+			return err
+		}
+	}
+
+	if len(rg.inventory.Failures) > 0 {
+		_, err = fmt.Fprintf(w, "### Resources Without Configuration (%d)\n\n", len(rg.inventory.Failures))
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(rg.inventory.Failures))
+		for i, f := range rg.inventory.Failures {
+			rows[i] = []string{f.Region.String(), f.ResourceType.String(), escapeMarkdown(f.ResourceID), escapeMarkdown(f.Reason)}
+		}
+		if err := writeMarkdownTable(w, []string{"Region", "Resource Type", "ID", "Reason"}, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
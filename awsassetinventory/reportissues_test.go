@@ -0,0 +1,91 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReportGenerator_Generate_CollectionIssues(t *testing.T) {
+	inv := groupTestInventory()
+	inv.Incomplete = true
+	inv.RegionStatus = []RegionStatus{
+		{Region: "eu-west-1", Status: RegionFailed, Error: "AccessDeniedException: not | authorized"},
+		{Region: "us-east-1", Status: RegionCollected, Resources: 3},
+		{Region: "us-west-2", Status: RegionCollected, Resources: 1},
+	}
+	inv.Failures = []ResourceFailure{
+		{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-east-1", Reason: "configuration not retrieved: throttled"},
+	}
+
+	var buf bytes.Buffer
+	if err := NewReportGenerator(inv).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	want := `**Completeness:** INCOMPLETE (2 of 3 regions collected, 1 resource without configuration)
+**Total Resources:** 4
+
+## Collection Issues
+
+> **Warning:** This inventory is incomplete. The counts and resources in this report leave out what could not be collected.
+
+### Regions
+
+| Region | Status | Resources | Error |
+|--------|--------|-----------|-------|
+| eu-west-1 | failed | 0 | AccessDeniedException: not \| authorized |
+| us-east-1 | collected | 3 | - |
+| us-west-2 | collected | 1 | - |
+
+### Resources Without Configuration (1)
+
+| Region | Resource Type | ID | Reason |
+|--------|---------------|----|--------|
+| us-east-1 | AWS::EC2::Instance | i-2 | configuration not retrieved: throttled |
+
+## Summary
+`
+	if !strings.Contains(output, want) {
+		t.Errorf("report should contain:\n%s\ngot:\n%s", want, output)
+	}
+	if strings.Contains(output, "**Status:**") {
+		t.Error("the completeness badge should replace the status line")
+	}
+}
+
+func TestReportGenerator_Generate_Complete(t *testing.T) {
+	inv := groupTestInventory()
+	inv.RegionStatus = []RegionStatus{
+		{Region: "us-east-1", Status: RegionCollected, Resources: 3},
+		{Region: "us-west-2", Status: RegionCollected, Resources: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := NewReportGenerator(inv).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "**Completeness:** COMPLETE (2 of 2 regions collected)\n") {
+		t.Errorf("report should have a complete badge, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "## Collection Issues") {
+		t.Error("a complete inventory should have no Collection Issues section")
+	}
+}
+
+func TestReportGenerator_Generate_WithoutCollectionStatus(t *testing.T) {
+	inv := groupTestInventory()
+	inv.Incomplete = true
+
+	var buf bytes.Buffer
+	if err := NewReportGenerator(inv).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Completeness") || strings.Contains(buf.String(), "## Collection Issues") {
+		t.Error("inventories without region status should keep the status line")
+	}
+	if !strings.Contains(buf.String(), "**Status:** Incomplete") {
+		t.Errorf("report = %s", buf.String())
+	}
+}
@@ -0,0 +1,257 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TypeCount is the number of resources of one type.
+type TypeCount struct {
+	ResourceType ResourceType
+	Count        int
+}
+
+// RegionCount is the number of resources in one region, in total and by
+// type.
+type RegionCount struct {
+	Region Region
+	Count  int
+	Types  []TypeCount
+}
+
+// ResourceGroup is the resources of one type.
+type ResourceGroup struct {
+	ResourceType ResourceType
+	Resources    []Resource
+}
+
+// ReportData is the data model of report templates. Counts and groups are
+// sorted by resource type or region, and the resources of a group by name
+// and ID, in the same order as the markdown report.
+type ReportData struct {
+	// Inventory is the full inventory, for fields not summarised below.
+	Inventory *Inventory
+
+	CollectedAt time.Time
+	GeneratedAt time.Time
+	Profile     string
+	Regions     []Region
+	Incomplete  bool
+	CountsOnly  bool
+
+	// IncludeDetails is set when resource details were asked for, such as
+	// with report --include-details. Templates may ignore it.
+	IncludeDetails bool
+
+	Total           int
+	CountsByType    []TypeCount
+	CountsByRegion  []RegionCount
+	ResourcesByType []ResourceGroup
+	Mismatches      []CountMismatch
+}
+
+// NewReportData builds the template data model for inv.
+func NewReportData(inv *Inventory) *ReportData {
+	d := &ReportData{
+		Inventory:   inv,
+		CollectedAt: inv.CollectedAt,
+		GeneratedAt: time.Now().UTC(),
+		Profile:     inv.Profile,
+		Regions:     inv.Regions,
+		Incomplete:  inv.Incomplete,
+		CountsOnly:  inv.CountsOnly,
+		Total:       inv.ResourceCount(),
+		Mismatches:  inv.Mismatches,
+	}
+
+	counts := inv.ResourceCountByType()
+	for _, rt := range sortedResourceTypes(counts) {
+		d.CountsByType = append(d.CountsByType, TypeCount{ResourceType: rt, Count: counts[rt]})
+	}
+
+	countsByRegion := inv.ResourceCountByTypeAndRegion()
+	for _, region := range sortedRegions(countsByRegion) {
+		rc := RegionCount{Region: region}
+		for _, rt := range sortedResourceTypes(countsByRegion[region]) {
+			n := countsByRegion[region][rt]
+			rc.Types = append(rc.Types, TypeCount{ResourceType: rt, Count: n})
+			rc.Count += n
+		}
+		d.CountsByRegion = append(d.CountsByRegion, rc)
+	}
+
+	grouped := inv.ResourcesByType()
+	for _, rt := range sortedResourceTypes(counts) {
+		if resources := grouped[rt]; len(resources) > 0 {
+			sortResources(resources)
+			d.ResourcesByType = append(d.ResourcesByType, ResourceGroup{ResourceType: rt, Resources: resources})
+		}
+	}
+	return d
+}
+
+// ReportTemplateFuncs returns the functions available to report
+// templates:
+//
+//	escapeMarkdown s       s with | escaped and newlines replaced, for markdown tables
+//	truncateARN s          s cut to 60 characters with "..."
+//	field name r           the value of an export column of r, such as "region",
+//	                       "tags.owner" or "config.instanceType"
+//	tag key r              the value of r's tag key, or ""
+//	hasTag key r           whether r has the tag key
+//	tagKeys r              r's tag keys, sorted
+//	sortResources rs       rs sorted by name and ID
+//	sortBy name rs         rs sorted by the value of an export column
+//	default d s            s, or d when s is empty
+//	join sep list          list joined with sep
+//	lower s, upper s       s in lower or upper case
+//	add a b                a + b
+//	percent part whole     part as a percentage of whole, with one decimal place
+func ReportTemplateFuncs() map[string]any {
+	return map[string]any{
+		"escapeMarkdown": escapeMarkdown,
+		"truncateARN":    truncateARN,
+		"field":          templateField,
+		"tag": func(key string, r Resource) string {
+			return r.Tags[key]
+		},
+		"hasTag": func(key string, r Resource) bool {
+			_, ok := r.Tags[key]
+			return ok
+		},
+		"tagKeys": func(r Resource) []string {
+			keys := make([]string, 0, len(r.Tags))
+			for k := range r.Tags {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return keys
+		},
+		"sortResources": func(resources []Resource) []Resource {
+			sorted := make([]Resource, len(resources))
+			copy(sorted, resources)
+			sortResources(sorted)
+			return sorted
+		},
+		"sortBy": templateSortBy,
+		"default": func(d, s string) string {
+			if s == "" {
+				return d
+			}
+			return s
+		},
+		"join": func(sep string, list any) (string, error) {
+			switch l := list.(type) {
+			case []string:
+				return strings.Join(l, sep), nil
+			case []Region:
+				parts := make([]string, len(l))
+				for i, r := range l {
+					parts[i] = r.String()
+				}
+				return strings.Join(parts, sep), nil
+			default:
+				return "", fmt.Errorf("join: cannot join %T", list)
+			}
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"add":   func(a, b int) int { return a + b },
+		"percent": func(part, whole int) string {
+			if whole == 0 {
+				return "0.0"
+			}
+			return fmt.Sprintf("%.1f", float64(part)*100/float64(whole))
+		},
+	}
+}
+
+func templateField(name string, r Resource) (string, error) {
+	col, err := parseExportColumn(name)
+	if err != nil {
+		return "", err
+	}
+	return col.Value(r), nil
+}
+
+func templateSortBy(name string, resources []Resource) ([]Resource, error) {
+	col, err := parseExportColumn(name)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]Resource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return col.Value(sorted[i]) < col.Value(sorted[j])
+	})
+	return sorted, nil
+}
+
+// ReportTemplate is a user-supplied report layout. Templates whose file
+// name ends in .html or .htm use html/template, which escapes values for
+// HTML; all others use text/template.
+type ReportTemplate struct {
+	name    string
+	execute func(w io.Writer, data any) error
+}
+
+// ParseReportTemplate reads and parses the template file at path with the
+// ReportTemplateFuncs.
+func ParseReportTemplate(path string) (*ReportTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(path)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		t, err := htmltemplate.New(name).Funcs(ReportTemplateFuncs()).Parse(string(data))
+		if err != nil {
+			return nil, err
+		}
+		return &ReportTemplate{name: name, execute: t.Execute}, nil
+	default:
+		t, err := template.New(name).Funcs(ReportTemplateFuncs()).Parse(string(data))
+		if err != nil {
+			return nil, err
+		}
+		return &ReportTemplate{name: name, execute: t.Execute}, nil
+	}
+}
+
+// Execute renders the template with data to w.
+func (t *ReportTemplate) Execute(w io.Writer, data *ReportData) error {
+	if err := t.execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", t.name, err)
+	}
+	return nil
+}
+
+// TemplateReportGenerator generates reports from inventory data with a
+// user-supplied ReportTemplate in place of the built-in layout.
+type TemplateReportGenerator struct {
+	inventory      *Inventory
+	template       *ReportTemplate
+	IncludeDetails bool
+}
+
+// NewTemplateReportGenerator creates a new TemplateReportGenerator for the
+// given inventory and template.
+func NewTemplateReportGenerator(inv *Inventory, tmpl *ReportTemplate) *TemplateReportGenerator {
+	return &TemplateReportGenerator{inventory: inv, template: tmpl}
+}
+
+// Generate renders the template with the inventory's ReportData to w.
+func (tg *TemplateReportGenerator) Generate(w io.Writer) error {
+	data := NewReportData(tg.inventory)
+	data.IncludeDetails = tg.IncludeDetails
+	return tg.template.Execute(w, data)
+}
@@ -0,0 +1,104 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplateFile(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewReportData(t *testing.T) {
+	d := NewReportData(htmlTestInventory())
+
+	if d.Total != 4 || d.Profile != "prod" || !d.Incomplete {
+		t.Errorf("metadata = %d %q %v", d.Total, d.Profile, d.Incomplete)
+	}
+	if len(d.CountsByType) != 2 || d.CountsByType[0] != (TypeCount{ResourceType: "AWS::EC2::Instance", Count: 3}) {
+		t.Errorf("CountsByType = %+v", d.CountsByType)
+	}
+	if len(d.CountsByRegion) != 2 || d.CountsByRegion[0].Region != "us-east-1" || d.CountsByRegion[0].Count != 3 || len(d.CountsByRegion[0].Types) != 2 {
+		t.Errorf("CountsByRegion = %+v", d.CountsByRegion)
+	}
+	if len(d.ResourcesByType) != 2 {
+		t.Fatalf("ResourcesByType = %+v", d.ResourcesByType)
+	}
+	var ids []string
+	for _, r := range d.ResourcesByType[0].Resources {
+		ids = append(ids, r.ResourceID)
+	}
+	if got := strings.Join(ids, ","); got != "i-2,i-3,i-1" {
+		t.Errorf("EC2 resources = %s, want sorted by name and ID", got)
+	}
+}
+
+func TestTemplateReportGenerator_Generate(t *testing.T) {
+	path := writeTemplateFile(t, "report.md.tmpl", `# {{.Profile}} ({{join ", " .Regions}})
+{{range .CountsByType}}- {{.ResourceType}}: {{.Count}} ({{percent .Count $.Total}}%)
+{{end}}{{range .ResourcesByType}}{{range sortBy "region" .Resources}}| {{.ResourceID}} | {{escapeMarkdown (default "-" .ResourceName)}} | {{tag "owner" .}} | {{field "config.instanceType" .}} |
+{{end}}{{end}}`)
+
+	tmpl, err := ParseReportTemplate(path)
+	if err != nil {
+		t.Fatalf("ParseReportTemplate() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := NewTemplateReportGenerator(htmlTestInventory(), tmpl).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `# prod (us-east-1, us-west-2)
+- AWS::EC2::Instance: 3 (75.0%)
+- AWS::S3::Bucket: 1 (25.0%)
+| i-3 | - |  |  |
+| i-1 | <script>alert(1)</script> | web | m5.large |
+| i-2 | - |  |  |
+| logs | - |  |  |
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseReportTemplate_HTMLEscapes(t *testing.T) {
+	path := writeTemplateFile(t, "report.html", `{{range .ResourcesByType}}{{range .Resources}}<p>{{.ResourceName}}</p>{{end}}{{end}}`)
+
+	tmpl, err := ParseReportTemplate(path)
+	if err != nil {
+		t.Fatalf("ParseReportTemplate() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := NewTemplateReportGenerator(htmlTestInventory(), tmpl).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>") {
+		t.Errorf(".html templates should escape values, got:\n%s", buf.String())
+	}
+}
+
+func TestParseReportTemplate_Errors(t *testing.T) {
+	if _, err := ParseReportTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := ParseReportTemplate(writeTemplateFile(t, "bad.tmpl", "{{.Total")); err == nil {
+		t.Error("expected an error for a malformed template")
+	}
+
+	tmpl, err := ParseReportTemplate(writeTemplateFile(t, "field.tmpl", `{{range .ResourcesByType}}{{range .Resources}}{{field "bogus field" .}}{{end}}{{end}}`))
+	if err != nil {
+		t.Fatalf("ParseReportTemplate() error = %v", err)
+	}
+	err = NewTemplateReportGenerator(htmlTestInventory(), tmpl).Generate(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "field.tmpl") {
+		t.Errorf("Generate() error = %v, want an error naming the template", err)
+	}
+}
@@ -27,8 +27,18 @@ func isRetryable(err error) bool {
 		strings.Contains(msg, "TooManyRequestsException")
 }
 
+// retryNotifyFunc is called each time fn fails with a retryable error.
+// attempt is the 1-based number of the failed call and delay is the backoff
+// before the next call, or zero when no retries remain.
+type retryNotifyFunc func(attempt int, err error, delay time.Duration)
+
 // retry executes fn with exponential backoff for retryable errors.
 func retry[T any](ctx context.Context, maxRetries int, fn func() (T, error)) (T, error) {
+	return retryNotify(ctx, maxRetries, nil, fn)
+}
+
+// retryNotify is retry with an optional notify callback for retryable failures.
+func retryNotify[T any](ctx context.Context, maxRetries int, notify retryNotifyFunc, fn func() (T, error)) (T, error) {
 	var result T
 	var err error
 	delay := DefaultBaseDelay
@@ -39,13 +49,23 @@ func retry[T any](ctx context.Context, maxRetries int, fn func() (T, error)) (T,
 			return result, nil
 		}
 
-		if !isRetryable(err) || attempt == maxRetries {
+		if !isRetryable(err) {
 			return result, err
 		}
 
 		// Add jitter: 50-150% of delay
-		jitter := time.Duration(rand.Int63n(int64(delay)))
-		sleep := delay + jitter/2
+		var sleep time.Duration
+		if attempt < maxRetries {
+			jitter := time.Duration(rand.Int63n(int64(delay)))
+			sleep = delay + jitter/2
+		}
+
+		if notify != nil {
+			notify(attempt+1, err, sleep)
+		}
+		if attempt == maxRetries {
+			return result, err
+		}
 
 		select {
 		case <-ctx.Done():
@@ -148,3 +148,44 @@ func TestRetry_ZeroRetries(t *testing.T) {
 		t.Errorf("retry() called %d times, want 1", callCount)
 	}
 }
+
+func TestRetryNotify_ReportsRetryableFailures(t *testing.T) {
+	type call struct {
+		attempt int
+		delay   time.Duration
+	}
+	var calls []call
+	notify := func(attempt int, err error, delay time.Duration) {
+		calls = append(calls, call{attempt, delay})
+	}
+
+	_, err := retryNotify(context.Background(), 1, notify, func() (string, error) {
+		return "", errors.New("ThrottlingException")
+	})
+
+	if err == nil {
+		t.Fatal("retryNotify() error = nil, want throttling error")
+	}
+	if len(calls) != 2 {
+		t.Fatalf("notify called %d times, want 2", len(calls))
+	}
+	if calls[0].attempt != 1 || calls[0].delay <= 0 {
+		t.Errorf("first notify = %+v, want attempt 1 with a backoff delay", calls[0])
+	}
+	if calls[1].attempt != 2 || calls[1].delay != 0 {
+		t.Errorf("last notify = %+v, want attempt 2 with no delay", calls[1])
+	}
+}
+
+func TestRetryNotify_IgnoresNonRetryable(t *testing.T) {
+	notified := false
+	_, _ = retryNotify(context.Background(), 3, func(int, error, time.Duration) {
+		notified = true
+	}, func() (string, error) {
+		return "", errors.New("AccessDeniedException")
+	})
+
+	if notified {
+		t.Error("notify should not be called for non-retryable errors")
+	}
+}
@@ -0,0 +1,310 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Field weights used to rank search results: a match on a resource's own
+// identifiers counts for more than a match deep in its configuration.
+var searchFieldWeights = map[string]float64{
+	"resourceId":       5,
+	"resourceName":     4,
+	"arn":              3,
+	"tags":             2,
+	"resourceType":     1,
+	"awsRegion":        1,
+	"availabilityZone": 1,
+	"accountId":        1,
+	"configuration":    1,
+}
+
+// SearchMatch is a field of a resource that matched a search. Path names
+// the field as it appears in the inventory JSON, for example resourceId,
+// tags.env or configuration.networkInterfaces[0].privateIpAddress.
+type SearchMatch struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// SearchResult is a resource that matched a search, with its score and the
+// fields that matched, best first.
+type SearchResult struct {
+	Resource Resource      `json:"resource"`
+	Score    float64       `json:"score"`
+	Matches  []SearchMatch `json:"matches"`
+}
+
+// searchField is a single searchable value of a resource.
+type searchField struct {
+	path   string
+	value  string
+	weight float64
+	// text is what the field is searched by, when it differs from value.
+	text string
+}
+
+func (f searchField) searchText() string {
+	if f.text != "" {
+		return f.text
+	}
+	return f.value
+}
+
+type searchPosting struct {
+	resource int
+	field    int
+}
+
+// SearchIndex is an in-memory inverted index over resources, their tags and
+// every scalar value in their configuration. It is safe for concurrent
+// searches once built.
+//
+// Values are split into lower-cased terms at any character other than a
+// letter, digit, '.', '-' or '_', so IP addresses, security group IDs and
+// hostnames stay whole while ARNs are split into their parts.
+type SearchIndex struct {
+	resources []Resource
+	fields    [][]searchField
+	postings  map[string][]searchPosting
+	terms     []string
+}
+
+// NewSearchIndex indexes resources.
+func NewSearchIndex(resources []Resource) *SearchIndex {
+	idx := &SearchIndex{
+		resources: resources,
+		fields:    make([][]searchField, len(resources)),
+		postings:  make(map[string][]searchPosting),
+	}
+	for i, r := range resources {
+		idx.fields[i] = resourceSearchFields(r)
+		for j, f := range idx.fields[i] {
+			for _, term := range searchTerms(f.searchText()) {
+				idx.postings[term] = append(idx.postings[term], searchPosting{resource: i, field: j})
+			}
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+	return idx
+}
+
+// Len returns the number of indexed resources.
+func (idx *SearchIndex) Len() int {
+	return len(idx.resources)
+}
+
+// Search returns the resources matching every term of query, best first. A
+// query term matches an indexed term that equals it or, at half the
+// weight, one that starts with it, so "sg-0ab" finds "sg-0abc123". A field
+// whose whole value equals the query scores extra.
+func (idx *SearchIndex) Search(query string) []SearchResult {
+	queryTerms := searchTerms(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	var candidates map[int]float64
+	matched := make(map[int]map[int]bool)
+	for _, qt := range queryTerms {
+		best := make(map[int]float64)
+		idx.eachTerm(qt, func(term string, postings []searchPosting) {
+			factor := 1.0
+			if term != qt {
+				factor = 0.5
+			}
+			for _, p := range postings {
+				w := idx.fields[p.resource][p.field].weight * factor
+				if w > best[p.resource] {
+					best[p.resource] = w
+				}
+				if matched[p.resource] == nil {
+					matched[p.resource] = make(map[int]bool)
+				}
+				matched[p.resource][p.field] = true
+			}
+		})
+
+		if candidates == nil {
+			candidates = best
+			continue
+		}
+		for r := range candidates {
+			if w, ok := best[r]; ok {
+				candidates[r] += w
+			} else {
+				delete(candidates, r)
+			}
+		}
+	}
+
+	whole := strings.ToLower(strings.TrimSpace(query))
+	results := make([]SearchResult, 0, len(candidates))
+	for r, score := range candidates {
+		for f := range matched[r] {
+			field := idx.fields[r][f]
+			if strings.ToLower(field.value) == whole {
+				score += field.weight
+			}
+		}
+		results = append(results, idx.result(r, score, matched[r]))
+	}
+	sortSearchResults(results)
+	return results
+}
+
+// SearchRegexp returns the resources with a field matching re, best first.
+// It scans every field rather than using the index.
+func (idx *SearchIndex) SearchRegexp(re *regexp.Regexp) []SearchResult {
+	results := make([]SearchResult, 0)
+	for r, fields := range idx.fields {
+		var score float64
+		matched := make(map[int]bool)
+		for f, field := range fields {
+			if re.MatchString(field.searchText()) {
+				score += field.weight
+				matched[f] = true
+			}
+		}
+		if len(matched) > 0 {
+			results = append(results, idx.result(r, score, matched))
+		}
+	}
+	sortSearchResults(results)
+	return results
+}
+
+// eachTerm calls fn for every indexed term that starts with prefix.
+func (idx *SearchIndex) eachTerm(prefix string, fn func(term string, postings []searchPosting)) {
+	for i := sort.SearchStrings(idx.terms, prefix); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		fn(idx.terms[i], idx.postings[idx.terms[i]])
+	}
+}
+
+func (idx *SearchIndex) result(r int, score float64, fields map[int]bool) SearchResult {
+	res := SearchResult{Resource: idx.resources[r], Score: score}
+	order := make([]int, 0, len(fields))
+	for f := range fields {
+		order = append(order, f)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := idx.fields[r][order[i]], idx.fields[r][order[j]]
+		if a.weight != b.weight {
+			return a.weight > b.weight
+		}
+		return order[i] < order[j]
+	})
+	for _, f := range order {
+		field := idx.fields[r][f]
+		res.Matches = append(res.Matches, SearchMatch{Path: field.path, Value: field.value})
+	}
+	return res
+}
+
+func sortSearchResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return lessByTypeRegionID(results[i].Resource, results[j].Resource)
+	})
+}
+
+// resourceSearchFields lists the searchable values of r.
+func resourceSearchFields(r Resource) []searchField {
+	var fields []searchField
+	add := func(path, value string) {
+		if value != "" {
+			fields = append(fields, searchField{path: path, value: value, weight: searchFieldWeights[path]})
+		}
+	}
+	add("resourceId", r.ResourceID)
+	add("resourceName", r.ResourceName)
+	add("arn", r.ARN)
+	add("resourceType", r.ResourceType.String())
+	add("awsRegion", r.Region.String())
+	add("availabilityZone", r.AvailabilityZone)
+	add("accountId", r.AccountID)
+
+	keys := make([]string, 0, len(r.Tags))
+	for k := range r.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, searchField{
+			path:   "tags." + k,
+			value:  r.Tags[k],
+			weight: searchFieldWeights["tags"],
+			text:   k + " " + r.Tags[k],
+		})
+	}
+
+	if len(r.Configuration) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(r.Configuration))
+		dec.UseNumber()
+		var config any
+		if dec.Decode(&config) == nil {
+			flattenSearchValues("configuration", config, func(path, value string) {
+				fields = append(fields, searchField{path: path, value: value, weight: searchFieldWeights["configuration"]})
+			})
+		}
+	}
+	return fields
+}
+
+// flattenSearchValues calls fn with the path and string form of every scalar
+// in v, visiting object keys in sorted order.
+func flattenSearchValues(path string, v any, fn func(path, value string)) {
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flattenSearchValues(path+"."+k, val[k], fn)
+		}
+	case []any:
+		for i, e := range val {
+			flattenSearchValues(fmt.Sprintf("%s[%d]", path, i), e, fn)
+		}
+	case string:
+		if val != "" {
+			fn(path, val)
+		}
+	case json.Number:
+		fn(path, val.String())
+	case bool:
+		fn(path, fmt.Sprint(val))
+	}
+}
+
+// searchTerms splits s into distinct lower-cased terms.
+func searchTerms(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '-' && r != '_'
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.Trim(w, ".-_")
+		if w != "" && !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,111 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func searchTestIndex() *SearchIndex {
+	return NewSearchIndex([]Resource{
+		{
+			ResourceType: "AWS::EC2::Instance", ResourceID: "i-0abc", ResourceName: "web", Region: "us-east-1", AccountID: "111111111111",
+			ARN:           "arn:aws:ec2:us-east-1:111111111111:instance/i-0abc",
+			Configuration: json.RawMessage(`{"privateIpAddress":"10.12.4.7","securityGroups":[{"groupId":"sg-0abc123"}],"ebsOptimized":true,"cpuOptions":{"coreCount":2}}`),
+			Tags:          map[string]string{"env": "prod", "Owner": "Platform Team"},
+		},
+		{
+			ResourceType: "AWS::EC2::SecurityGroup", ResourceID: "sg-0abc123", Region: "us-east-1", AccountID: "111111111111",
+			Configuration: json.RawMessage(`{"ipPermissions":[{"ipRanges":["10.12.4.7/32"]}]}`),
+		},
+		{
+			ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2", AccountID: "111111111111",
+			Configuration: json.RawMessage(`{"privateIpAddress":"10.12.4.70"}`),
+		},
+	})
+}
+
+func resultIDs(results []SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Resource.ResourceID
+	}
+	return ids
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	idx := searchTestIndex()
+	if idx.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", idx.Len())
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Exact matches rank above the prefix match on 10.12.4.70.
+		{"10.12.4.7", []string{"i-0abc", "sg-0abc123", "i-2"}},
+		// A match on the resource ID outranks one in another resource's configuration.
+		{"sg-0abc123", []string{"sg-0abc123", "i-0abc"}},
+		{"SG-0ABC", []string{"sg-0abc123", "i-0abc"}},
+		{"web prod", []string{"i-0abc"}},
+		{"platform team", []string{"i-0abc"}},
+		{"owner", []string{"i-0abc"}},
+		{"us-west-2", []string{"i-2"}},
+		{"nothing-like-this", []string{}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := idx.Search(tt.query)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("Search(%q) = %v, want nil", tt.query, resultIDs(got))
+				}
+				return
+			}
+			if ids := resultIDs(got); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchIndex_SearchMatches(t *testing.T) {
+	results := searchTestIndex().Search("10.12.4.7")
+	if len(results) == 0 {
+		t.Fatal("Search() returned no results")
+	}
+	want := []SearchMatch{{Path: "configuration.privateIpAddress", Value: "10.12.4.7"}}
+	if !reflect.DeepEqual(results[0].Matches, want) {
+		t.Errorf("Matches = %+v, want %+v", results[0].Matches, want)
+	}
+
+	results = searchTestIndex().Search("sg-0abc123")
+	if results[1].Matches[0].Path != "configuration.securityGroups[0].groupId" {
+		t.Errorf("Matches = %+v, want the array path", results[1].Matches)
+	}
+}
+
+func TestSearchIndex_SearchRegexp(t *testing.T) {
+	idx := searchTestIndex()
+
+	got := idx.SearchRegexp(regexp.MustCompile(`^10\.12\.4\.\d+$`))
+	if ids := resultIDs(got); !reflect.DeepEqual(ids, []string{"i-0abc", "i-2"}) {
+		t.Errorf("SearchRegexp() = %v", ids)
+	}
+
+	got = idx.SearchRegexp(regexp.MustCompile(`^true$`))
+	if len(got) != 1 || got[0].Matches[0].Path != "configuration.ebsOptimized" {
+		t.Errorf("SearchRegexp() should match scalar configuration values, got %+v", got)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms("arn:aws:ec2:us-east-1:123:instance/i-0ABC, 10.0.0.0/16 example.com. Team Team")
+	want := []string{"arn", "aws", "ec2", "us-east-1", "123", "instance", "i-0abc", "10.0.0.0", "16", "example.com", "team"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("searchTerms() = %v, want %v", got, want)
+	}
+}
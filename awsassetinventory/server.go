@@ -0,0 +1,267 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// InventorySource returns the inventory a Server answers from. It is called
+// for every request, so the inventory may change between requests, as it
+// does for a Daemon or a snapshot directory.
+type InventorySource func() (*Inventory, error)
+
+// StaticSource returns an InventorySource that always serves inv.
+func StaticSource(inv *Inventory) InventorySource {
+	return func() (*Inventory, error) { return inv, nil }
+}
+
+// Server is a read-only HTTP API over an inventory:
+//
+//	GET /resources              resources, filtered by type, region, account and tag
+//	GET /resources/{arn}        a single resource by ARN
+//	GET /counts/by-type         resource counts per type
+//	GET /counts/by-region       resource counts per region
+//	GET /report                 the markdown report; details=true includes resource details
+//	GET /metrics                inventory and collection metrics for Prometheus
+//
+// /resources accepts repeated tag parameters, each either key=value or a bare
+// key that only has to be present, and limit and offset for paging.
+//
+// Outcome, when set, supplies the collection metrics for /metrics; it may
+// return nil before any collection has run.
+type Server struct {
+	Outcome func() *CollectionOutcome
+
+	source InventorySource
+	mux    *http.ServeMux
+}
+
+// NewServer creates a Server that answers from source.
+func NewServer(source InventorySource) *Server {
+	s := &Server{source: source, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /resources", s.handleResources)
+	s.mux.HandleFunc("GET /resources/{arn...}", s.handleResource)
+	s.mux.HandleFunc("GET /counts/by-type", s.handleCountsByType)
+	s.mux.HandleFunc("GET /counts/by-region", s.handleCountsByRegion)
+	s.mux.HandleFunc("GET /report", s.handleReport)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// resourceList is the response body of /resources.
+type resourceList struct {
+	Total     int        `json:"total"`
+	Count     int        `json:"count"`
+	Resources []Resource `json:"resources"`
+}
+
+func (s *Server) inventory(w http.ResponseWriter) (*Inventory, bool) {
+	inv, err := s.source()
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrNoSnapshots) || errors.Is(err, ErrNoInventory) {
+			status = http.StatusServiceUnavailable
+		}
+		writeError(w, status, err)
+		return nil, false
+	}
+	return inv, true
+}
+
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, err := queryInt(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %w", err))
+		return
+	}
+	offset, err := queryInt(q.Get("offset"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid offset: %w", err))
+		return
+	}
+
+	inv, ok := s.inventory(w)
+	if !ok {
+		return
+	}
+
+	filter := resourceFilter{
+		resourceType: ResourceType(q.Get("type")),
+		region:       Region(q.Get("region")),
+		account:      q.Get("account"),
+		tags:         q["tag"],
+	}
+
+	matched := make([]Resource, 0)
+	for _, res := range inv.Resources {
+		if filter.matches(res) {
+			matched = append(matched, res)
+		}
+	}
+
+	total := len(matched)
+	if offset > total {
+		offset = total
+	}
+	matched = matched[offset:]
+	if limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, resourceList{Total: total, Count: len(matched), Resources: matched})
+}
+
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request) {
+	arn := r.PathValue("arn")
+
+	inv, ok := s.inventory(w)
+	if !ok {
+		return
+	}
+
+	for _, res := range inv.Resources {
+		if res.ARN != "" && res.ARN == arn {
+			writeJSON(w, http.StatusOK, res)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("resource not found: %s", arn))
+}
+
+func (s *Server) handleCountsByType(w http.ResponseWriter, r *http.Request) {
+	inv, ok := s.inventory(w)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, inv.ResourceCountByType())
+}
+
+func (s *Server) handleCountsByRegion(w http.ResponseWriter, r *http.Request) {
+	inv, ok := s.inventory(w)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, inv.ResourceCountByRegion())
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	details, err := queryBool(r.URL.Query().Get("details"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid details: %w", err))
+		return
+	}
+
+	inv, ok := s.inventory(w)
+	if !ok {
+		return
+	}
+
+	rg := NewReportGenerator(inv)
+	rg.IncludeDetails = details
+
+	var sb strings.Builder
+	if err := rg.Generate(&sb); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	_, _ = w.Write([]byte(sb.String()))
+}
+
+// handleMetrics serves metrics even before an inventory is available, so
+// that failed and stale collections can be alerted on.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	inv, err := s.source()
+	if err != nil && !errors.Is(err, ErrNoSnapshots) && !errors.Is(err, ErrNoInventory) {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var outcome *CollectionOutcome
+	if s.Outcome != nil {
+		outcome = s.Outcome()
+	}
+
+	var sb strings.Builder
+	if err := WriteMetrics(&sb, inv, outcome); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(sb.String()))
+}
+
+// resourceFilter selects resources by exact type, region and account, and
+// by tags given as key=value or as a bare key. Empty fields match anything.
+type resourceFilter struct {
+	resourceType ResourceType
+	region       Region
+	account      string
+	tags         []string
+}
+
+func (f resourceFilter) matches(r Resource) bool {
+	if f.resourceType != "" && r.ResourceType != f.resourceType {
+		return false
+	}
+	if f.region != "" && r.Region != f.region {
+		return false
+	}
+	if f.account != "" && r.AccountID != f.account {
+		return false
+	}
+	for _, tag := range f.tags {
+		key, value, hasValue := strings.Cut(tag, "=")
+		got, ok := r.Tags[key]
+		if !ok || (hasValue && got != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func queryInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return n, nil
+}
+
+func queryBool(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
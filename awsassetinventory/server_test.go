@@ -0,0 +1,209 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServerInventory() *Inventory {
+	inv := NewInventory("test", []Region{"us-east-1", "us-west-2"})
+	inv.AddResource(Resource{
+		ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1", AccountID: "111111111111",
+		ARN:  "arn:aws:ec2:us-east-1:111111111111:instance/i-1",
+		Tags: map[string]string{"env": "prod", "team": "web"},
+	})
+	inv.AddResource(Resource{
+		ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2", AccountID: "222222222222",
+		ARN:  "arn:aws:ec2:us-west-2:222222222222:instance/i-2",
+		Tags: map[string]string{"env": "dev"},
+	})
+	inv.AddResource(Resource{
+		ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1", AccountID: "111111111111",
+		ARN: "arn:aws:s3:::logs",
+	})
+	return inv
+}
+
+func serve(t *testing.T, s *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestServer_Resources(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"all", "/resources", []string{"i-1", "i-2", "logs"}},
+		{"by type", "/resources?type=AWS::EC2::Instance", []string{"i-1", "i-2"}},
+		{"by region", "/resources?region=us-east-1", []string{"i-1", "logs"}},
+		{"by account", "/resources?account=222222222222", []string{"i-2"}},
+		{"by tag value", "/resources?tag=env=prod", []string{"i-1"}},
+		{"by tag key", "/resources?tag=env", []string{"i-1", "i-2"}},
+		{"by several tags", "/resources?tag=env=prod&tag=team=web", []string{"i-1"}},
+		{"combined", "/resources?type=AWS::EC2::Instance&region=us-east-1", []string{"i-1"}},
+		{"no match", "/resources?tag=owner", []string{}},
+		{"limit and offset", "/resources?limit=1&offset=1", []string{"i-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, s, tt.target)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET %s status = %d, want 200", tt.target, rec.Code)
+			}
+			var got resourceList
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			if len(got.Resources) != len(tt.want) {
+				t.Fatalf("GET %s returned %d resources, want %d", tt.target, len(got.Resources), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got.Resources[i].ResourceID != id {
+					t.Errorf("GET %s resources[%d] = %s, want %s", tt.target, i, got.Resources[i].ResourceID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ResourcesPagingTotal(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+	rec := serve(t, s, "/resources?limit=2")
+
+	var got resourceList
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 3 || got.Count != 2 {
+		t.Errorf("total, count = %d, %d, want 3, 2", got.Total, got.Count)
+	}
+}
+
+func TestServer_ResourcesInvalidLimit(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+	if rec := serve(t, s, "/resources?limit=-1"); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestServer_ResourceByARN(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+
+	rec := serve(t, s, "/resources/arn:aws:ec2:us-west-2:222222222222:instance/i-2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got Resource
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ResourceID != "i-2" {
+		t.Errorf("resource = %s, want i-2", got.ResourceID)
+	}
+
+	if rec := serve(t, s, "/resources/arn:aws:s3:::missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing ARN status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServer_Counts(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+
+	var byType map[ResourceType]int
+	if err := json.Unmarshal(serve(t, s, "/counts/by-type").Body.Bytes(), &byType); err != nil {
+		t.Fatal(err)
+	}
+	if byType["AWS::EC2::Instance"] != 2 || byType["AWS::S3::Bucket"] != 1 {
+		t.Errorf("/counts/by-type = %v", byType)
+	}
+
+	var byRegion map[Region]int
+	if err := json.Unmarshal(serve(t, s, "/counts/by-region").Body.Bytes(), &byRegion); err != nil {
+		t.Fatal(err)
+	}
+	if byRegion["us-east-1"] != 2 || byRegion["us-west-2"] != 1 {
+		t.Errorf("/counts/by-region = %v", byRegion)
+	}
+}
+
+func TestServer_Report(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+
+	rec := serve(t, s, "/report")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/markdown") {
+		t.Errorf("Content-Type = %s, want text/markdown", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "# AWS Asset Inventory Report") {
+		t.Errorf("report body = %s", rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "## Resource Details") {
+		t.Error("report should omit details unless details=true")
+	}
+	if !strings.Contains(serve(t, s, "/report?details=true").Body.String(), "## Resource Details") {
+		t.Error("report?details=true should include details")
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/resources", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want 405", rec.Code)
+	}
+}
+
+func TestServer_SourceErrors(t *testing.T) {
+	s := NewServer(func() (*Inventory, error) { return nil, ErrNoSnapshots })
+	if rec := serve(t, s, "/resources"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no snapshots status = %d, want 503", rec.Code)
+	}
+
+	s = NewServer(func() (*Inventory, error) { return nil, errors.New("corrupt") })
+	rec := serve(t, s, "/counts/by-type")
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "corrupt") {
+		t.Errorf("source error response = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	s := NewServer(StaticSource(newTestServerInventory()))
+	s.Outcome = func() *CollectionOutcome { return &CollectionOutcome{Success: true, Runs: 1} }
+
+	rec := serve(t, s, "/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %s", rec.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{"aws_asset_inventory_resources_total 3", "aws_asset_inventory_collection_success 1"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics should contain %q", want)
+		}
+	}
+}
+
+func TestServer_MetricsWithoutInventory(t *testing.T) {
+	s := NewServer(func() (*Inventory, error) { return nil, ErrNoInventory })
+	s.Outcome = func() *CollectionOutcome { return &CollectionOutcome{Runs: 1, FailedRuns: 1} }
+
+	rec := serve(t, s, "/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "aws_asset_inventory_collection_failed_runs_total 1") {
+		t.Errorf("metrics should report the failed run, got:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,191 @@
+package awsassetinventory
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	snapshotPrefix     = "inventory-"
+	snapshotSuffix     = ".json"
+	snapshotTimeFormat = "20060102T150405.000Z"
+)
+
+// ErrNoSnapshots is returned by SnapshotDir.Latest when the directory holds
+// no snapshots.
+var ErrNoSnapshots = errors.New("no inventory snapshots found")
+
+// SnapshotDir stores inventory snapshots as timestamped JSON files in a
+// directory. File names sort in collection order, so the newest snapshot is
+// always last.
+//
+// Retain is the number of snapshots Prune keeps; 0 keeps all of them.
+type SnapshotDir struct {
+	Path   string
+	Retain int
+}
+
+// NewSnapshotDir creates a SnapshotDir for path that keeps the newest retain
+// snapshots.
+func NewSnapshotDir(path string, retain int) *SnapshotDir {
+	return &SnapshotDir{Path: path, Retain: retain}
+}
+
+// SnapshotName returns the file name a snapshot collected at t is stored as,
+// such as inventory-20240115T103000.250Z.json.
+func SnapshotName(t time.Time) string {
+	return snapshotPrefix + t.UTC().Format(snapshotTimeFormat) + snapshotSuffix
+}
+
+// Write stores inv as a new snapshot named after its collection time and
+// returns the snapshot's path. The file is written to a temporary name first
+// so readers never see a partial snapshot. Existing snapshots are never
+// replaced: if the name is taken, such as by another run collected in the
+// same millisecond, the snapshot is named a millisecond later instead.
+func (d *SnapshotDir) Write(inv *Inventory) (string, error) {
+	if err := os.MkdirAll(d.Path, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.Path, ".snapshot-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	// Linking, unlike renaming, fails rather than replacing an existing
+	// snapshot.
+	for t := inv.CollectedAt; ; t = t.Add(time.Millisecond) {
+		path := filepath.Join(d.Path, SnapshotName(t))
+		err := os.Link(tmp.Name(), path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+}
+
+// List returns the paths of all snapshots in the directory, oldest first.
+// A missing directory holds no snapshots.
+func (d *SnapshotDir) List() ([]string, error) {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && strings.HasPrefix(name, snapshotPrefix) && strings.HasSuffix(name, snapshotSuffix) {
+			paths = append(paths, filepath.Join(d.Path, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Latest loads the newest snapshot. It returns ErrNoSnapshots when there is
+// none.
+func (d *SnapshotDir) Latest() (*Inventory, error) {
+	paths, err := d.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, ErrNoSnapshots
+	}
+	return LoadFromFile(paths[len(paths)-1])
+}
+
+// Source returns an InventorySource that serves the newest snapshot in the
+// directory. A snapshot is loaded again only when a newer one appears.
+func (d *SnapshotDir) Source() InventorySource {
+	var mu sync.Mutex
+	var latestPath string
+	var latest *Inventory
+
+	return func() (*Inventory, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		paths, err := d.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, ErrNoSnapshots
+		}
+		if path := paths[len(paths)-1]; path != latestPath {
+			inv, err := LoadFromFile(path)
+			if err != nil {
+				return nil, err
+			}
+			latestPath, latest = path, inv
+		}
+		return latest, nil
+	}
+}
+
+// Prune removes all but the newest Retain snapshots and returns the paths it
+// removed.
+func (d *SnapshotDir) Prune() ([]string, error) {
+	if d.Retain <= 0 {
+		return nil, nil
+	}
+
+	paths, err := d.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) <= d.Retain {
+		return nil, nil
+	}
+
+	stale := paths[:len(paths)-d.Retain]
+	for i, p := range stale {
+		if err := os.Remove(p); err != nil {
+			return stale[:i], fmt.Errorf("failed to remove snapshot: %w", err)
+		}
+	}
+	return stale, nil
+}
+
+// LoadFromFile reads and deserializes an inventory JSON file.
+func LoadFromFile(path string) (*Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := LoadFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return inv, nil
+}
@@ -0,0 +1,187 @@
+package awsassetinventory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSnapshots(t *testing.T, d *SnapshotDir, n int) []string {
+	t.Helper()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	paths := make([]string, n)
+	for i := 0; i < n; i++ {
+		inv := NewInventory("test", []Region{"us-east-1"})
+		inv.CollectedAt = base.Add(time.Duration(i) * time.Hour)
+		inv.AddResource(Resource{ResourceID: inv.CollectedAt.Format(time.RFC3339)})
+		path, err := d.Write(inv)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		paths[i] = path
+	}
+	return paths
+}
+
+func TestSnapshotName(t *testing.T) {
+	got := SnapshotName(time.Date(2024, 1, 15, 10, 30, 0, 250*int(time.Millisecond), time.UTC))
+	if got != "inventory-20240115T103000.250Z.json" {
+		t.Errorf("SnapshotName() = %s, want inventory-20240115T103000.250Z.json", got)
+	}
+}
+
+func TestSnapshotDir_WriteAndLatest(t *testing.T) {
+	d := NewSnapshotDir(filepath.Join(t.TempDir(), "snapshots"), 0)
+	paths := writeSnapshots(t, d, 3)
+
+	if filepath.Base(paths[0]) != "inventory-20240115T100000.000Z.json" {
+		t.Errorf("Write() path = %s, want inventory-20240115T100000.000Z.json", paths[0])
+	}
+
+	latest, err := d.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Resources[0].ResourceID != "2024-01-15T12:00:00Z" {
+		t.Errorf("Latest() = snapshot %s, want 2024-01-15T12:00:00Z", latest.Resources[0].ResourceID)
+	}
+
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("snapshot directory has %d entries, want 3 (no temporary files)", len(entries))
+	}
+}
+
+func TestSnapshotDir_WriteSameTime(t *testing.T) {
+	d := NewSnapshotDir(t.TempDir(), 0)
+	collectedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for _, id := range []string{"first", "second", "third"} {
+		inv := NewInventory("test", []Region{"us-east-1"})
+		inv.CollectedAt = collectedAt
+		inv.AddResource(Resource{ResourceID: id})
+		path, err := d.Write(inv)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		paths = append(paths, filepath.Base(path))
+	}
+
+	want := []string{
+		"inventory-20240115T100000.000Z.json",
+		"inventory-20240115T100000.001Z.json",
+		"inventory-20240115T100000.002Z.json",
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Write() paths = %v, want %v", paths, want)
+			break
+		}
+	}
+
+	latest, err := d.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Resources[0].ResourceID != "third" {
+		t.Errorf("Latest() = snapshot %s, want third", latest.Resources[0].ResourceID)
+	}
+}
+
+func TestSnapshotDir_ListIgnoresOtherFiles(t *testing.T) {
+	d := NewSnapshotDir(t.TempDir(), 0)
+	writeSnapshots(t, d, 2)
+	if err := os.WriteFile(filepath.Join(d.Path, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := d.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("List() = %v, want 2 snapshots", paths)
+	}
+}
+
+func TestSnapshotDir_LatestEmpty(t *testing.T) {
+	d := NewSnapshotDir(filepath.Join(t.TempDir(), "missing"), 0)
+	if _, err := d.Latest(); !errors.Is(err, ErrNoSnapshots) {
+		t.Errorf("Latest() error = %v, want ErrNoSnapshots", err)
+	}
+}
+
+func TestSnapshotDir_Prune(t *testing.T) {
+	d := NewSnapshotDir(t.TempDir(), 2)
+	paths := writeSnapshots(t, d, 5)
+
+	removed, err := d.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 3 || removed[0] != paths[0] || removed[2] != paths[2] {
+		t.Errorf("Prune() removed %v, want the 3 oldest", removed)
+	}
+
+	remaining, err := d.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 || remaining[1] != paths[4] {
+		t.Errorf("List() after Prune() = %v, want the 2 newest", remaining)
+	}
+}
+
+func TestSnapshotDir_PruneRetainAll(t *testing.T) {
+	d := NewSnapshotDir(t.TempDir(), 0)
+	writeSnapshots(t, d, 3)
+
+	removed, err := d.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("Prune() with Retain 0 removed %v, want none", removed)
+	}
+}
+
+func TestSnapshotDir_Source(t *testing.T) {
+	d := NewSnapshotDir(t.TempDir(), 0)
+	source := d.Source()
+
+	if _, err := source(); !errors.Is(err, ErrNoSnapshots) {
+		t.Errorf("source() on empty directory error = %v, want ErrNoSnapshots", err)
+	}
+
+	writeSnapshots(t, d, 1)
+	first, err := source()
+	if err != nil {
+		t.Fatalf("source() error = %v", err)
+	}
+	again, err := source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("source() should reuse the loaded snapshot until a newer one appears")
+	}
+
+	inv := NewInventory("test", nil)
+	inv.CollectedAt = first.CollectedAt.Add(24 * time.Hour)
+	if _, err := d.Write(inv); err != nil {
+		t.Fatal(err)
+	}
+	newer, err := source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newer.CollectedAt.Equal(inv.CollectedAt) {
+		t.Errorf("source() CollectedAt = %v, want the newer snapshot", newer.CollectedAt)
+	}
+}
@@ -0,0 +1,410 @@
+package awsassetinventory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	// Register the pure-Go SQLite driver as "sqlite".
+	_ "modernc.org/sqlite"
+)
+
+// ErrSnapshotNotFound is returned by Store.Load for an unknown snapshot ID.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrMultipleStatements is returned by Store.Query for SQL with more than one
+// statement.
+var ErrMultipleStatements = errors.New("query must be a single SQL statement")
+
+// storeSchema creates the store's tables. snapshots.metadata holds the
+// inventory JSON without its resources, so fields not mapped to columns
+// survive a round trip; resources are stored one row each so they can be
+// queried across snapshots.
+const storeSchema = `
+CREATE TABLE IF NOT EXISTS snapshots (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	collected_at   TEXT    NOT NULL,
+	profile        TEXT    NOT NULL,
+	regions        TEXT    NOT NULL,
+	incomplete     INTEGER NOT NULL,
+	counts_only    INTEGER NOT NULL,
+	resource_count INTEGER NOT NULL,
+	metadata       TEXT    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS resources (
+	snapshot_id       INTEGER NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+	resource_type     TEXT    NOT NULL,
+	resource_id       TEXT    NOT NULL,
+	resource_name     TEXT,
+	region            TEXT    NOT NULL,
+	availability_zone TEXT,
+	account_id        TEXT,
+	arn               TEXT,
+	configuration     TEXT,
+	tags              TEXT,
+	source            TEXT
+);
+
+CREATE INDEX IF NOT EXISTS resources_snapshot ON resources(snapshot_id);
+CREATE INDEX IF NOT EXISTS resources_arn ON resources(arn);
+CREATE INDEX IF NOT EXISTS resources_type_region ON resources(resource_type, region);
+`
+
+// storeTimeFormat sorts lexically in time order, so collected_at can be
+// compared and ordered in SQL.
+const storeTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// Store persists inventory snapshots in an embedded SQLite database.
+//
+// Each snapshot is a row in the snapshots table, and each of its resources a
+// row in the resources table with configuration and tags as JSON text, so
+// history can be queried with plain SQL, for example:
+//
+//	SELECT MIN(s.collected_at) FROM resources r
+//	JOIN snapshots s ON s.id = r.snapshot_id
+//	WHERE r.arn = 'arn:aws:s3:::my-bucket'
+type Store struct {
+	db *sql.DB
+	// ro is a read-only handle on the same file for ad-hoc queries.
+	ro *sql.DB
+}
+
+// SnapshotInfo describes a stored snapshot without loading its resources.
+type SnapshotInfo struct {
+	ID            int64     `json:"id"`
+	CollectedAt   time.Time `json:"collectedAt"`
+	Profile       string    `json:"profile"`
+	Regions       []Region  `json:"regions"`
+	Incomplete    bool      `json:"incomplete,omitempty"`
+	CountsOnly    bool      `json:"countsOnly,omitempty"`
+	ResourceCount int       `json:"resourceCount"`
+}
+
+// QueryResult holds the rows of an ad-hoc query, with every value formatted
+// as text. NULL values are empty strings.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// OpenStore opens the SQLite database at path, creating it and its tables
+// if needed.
+func OpenStore(path string) (*Store, error) {
+	name := (&url.URL{Scheme: "file", Path: path}).String()
+	db, err := sql.Open("sqlite", name+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	if _, err := db.Exec(storeSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise store: %w", err)
+	}
+	if err := migrateStore(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to upgrade store: %w", err)
+	}
+	ro, err := sql.Open("sqlite", name+"?mode=ro&_pragma=busy_timeout(5000)&_pragma=query_only(1)")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	// Settings a query changes, such as query_only or an attached database,
+	// must not carry over to the next query.
+	ro.SetMaxIdleConns(0)
+	return &Store{db: db, ro: ro}, nil
+}
+
+// migrateStore adds the columns that stores created by earlier versions
+// lack.
+func migrateStore(db *sql.DB) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('resources')`)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !columns["source"] {
+		if _, err := db.Exec(`ALTER TABLE resources ADD COLUMN source TEXT`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return errors.Join(s.ro.Close(), s.db.Close())
+}
+
+// Save stores inv as a new snapshot and returns its ID.
+func (s *Store) Save(ctx context.Context, inv *Inventory) (int64, error) {
+	meta := *inv
+	meta.Resources = nil
+	metadata, err := json.Marshal(meta)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize snapshot metadata: %w", err)
+	}
+	regions, err := json.Marshal(inv.Regions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize snapshot regions: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO snapshots (collected_at, profile, regions, incomplete, counts_only, resource_count, metadata)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		inv.CollectedAt.UTC().Format(storeTimeFormat), inv.Profile, string(regions),
+		inv.Incomplete, inv.CountsOnly, inv.ResourceCount(), string(metadata))
+	if err != nil {
+		return 0, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO resources (snapshot_id, resource_type, resource_id, resource_name, region,
+		 availability_zone, account_id, arn, configuration, tags, source)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, r := range inv.Resources {
+		var tags any
+		if len(r.Tags) > 0 {
+			data, err := json.Marshal(r.Tags)
+			if err != nil {
+				return 0, fmt.Errorf("failed to serialize tags for %s: %w", r.ResourceID, err)
+			}
+			tags = string(data)
+		}
+		_, err := stmt.ExecContext(ctx, id, string(r.ResourceType), r.ResourceID, nullString(r.ResourceName),
+			string(r.Region), nullString(r.AvailabilityZone), nullString(r.AccountID), nullString(r.ARN),
+			nullString(string(r.Configuration)), tags, nullString(r.Source))
+		if err != nil {
+			return 0, fmt.Errorf("failed to store resource %s: %w", r.ResourceID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return id, nil
+}
+
+// Snapshots lists stored snapshots, oldest first.
+func (s *Store) Snapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, collected_at, profile, regions, incomplete, counts_only, resource_count
+		 FROM snapshots ORDER BY collected_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []SnapshotInfo
+	for rows.Next() {
+		var info SnapshotInfo
+		var collectedAt, regions string
+		if err := rows.Scan(&info.ID, &collectedAt, &info.Profile, &regions,
+			&info.Incomplete, &info.CountsOnly, &info.ResourceCount); err != nil {
+			return nil, err
+		}
+		if info.CollectedAt, err = time.Parse(storeTimeFormat, collectedAt); err != nil {
+			return nil, fmt.Errorf("snapshot %d: invalid collection time: %w", info.ID, err)
+		}
+		if err := json.Unmarshal([]byte(regions), &info.Regions); err != nil {
+			return nil, fmt.Errorf("snapshot %d: invalid regions: %w", info.ID, err)
+		}
+		snapshots = append(snapshots, info)
+	}
+	return snapshots, rows.Err()
+}
+
+// LatestID returns the ID of the most recently collected snapshot.
+func (s *Store) LatestID(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id FROM snapshots ORDER BY collected_at DESC, id DESC LIMIT 1`).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNoSnapshots
+	}
+	return id, err
+}
+
+// Load reads the snapshot with the given ID back into an Inventory.
+func (s *Store) Load(ctx context.Context, id int64) (*Inventory, error) {
+	var metadata string
+	err := s.db.QueryRowContext(ctx, `SELECT metadata FROM snapshots WHERE id = ?`, id).Scan(&metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := LoadFromJSON([]byte(metadata))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %d: invalid metadata: %w", id, err)
+	}
+	inv.Resources = make([]Resource, 0)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT resource_type, resource_id, resource_name, region, availability_zone,
+		 account_id, arn, configuration, tags, source
+		 FROM resources WHERE snapshot_id = ? ORDER BY rowid`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r Resource
+		var name, az, account, arn, configuration, tags, source sql.NullString
+		if err := rows.Scan(&r.ResourceType, &r.ResourceID, &name, &r.Region, &az,
+			&account, &arn, &configuration, &tags, &source); err != nil {
+			return nil, err
+		}
+		r.ResourceName, r.AvailabilityZone, r.AccountID, r.ARN = name.String, az.String, account.String, arn.String
+		r.Source = source.String
+		if configuration.Valid {
+			r.Configuration = json.RawMessage(configuration.String)
+		}
+		if tags.Valid {
+			if err := json.Unmarshal([]byte(tags.String), &r.Tags); err != nil {
+				return nil, fmt.Errorf("snapshot %d: invalid tags for %s: %w", id, r.ResourceID, err)
+			}
+		}
+		inv.AddResource(r)
+	}
+	return inv, rows.Err()
+}
+
+// Query runs an ad-hoc SQL query across all snapshots. The query must be a
+// single statement, and runs on a fresh connection that opened the database
+// read-only, so statements that would modify the database fail.
+func (s *Store) Query(ctx context.Context, query string, args ...any) (*QueryResult, error) {
+	if hasMultipleStatements(query) {
+		return nil, ErrMultipleStatements
+	}
+	rows, err := s.ro.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{Columns: columns}
+
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = formatSQLValue(v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+// hasMultipleStatements reports whether query has anything other than
+// whitespace and comments after a semicolon that ends a statement. Semicolons
+// inside quotes and comments are skipped.
+func hasMultipleStatements(query string) bool {
+	ended := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return false
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+		case c == ';':
+			ended = true
+		default:
+			if ended {
+				return true
+			}
+			if closing, ok := sqlQuotes[c]; ok {
+				end := strings.IndexByte(query[i+1:], closing)
+				if end < 0 {
+					return false
+				}
+				// A doubled quote is an escaped one and continues the
+				// literal, which the next iteration rescans.
+				i += end + 1
+			}
+		}
+	}
+	return false
+}
+
+// sqlQuotes maps the characters that open a SQLite string literal or quoted
+// identifier to the character that closes it.
+var sqlQuotes = map[byte]byte{'\'': '\'', '"': '"', '`': '`', '[': ']'}
+
+func formatSQLValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// nullString maps empty strings to SQL NULL.
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,259 @@
+package awsassetinventory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := OpenStore(filepath.Join(t.TempDir(), "inventory.sqlite"))
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func newStoreInventory(at time.Time, ids ...string) *Inventory {
+	inv := NewInventory("prod", []Region{"us-east-1", "us-west-2"})
+	inv.CollectedAt = at
+	for _, id := range ids {
+		inv.AddResource(Resource{
+			ResourceType:  "AWS::S3::Bucket",
+			ResourceID:    id,
+			Region:        "us-east-1",
+			AccountID:     "123456789012",
+			ARN:           "arn:aws:s3:::" + id,
+			Configuration: json.RawMessage(`{"name":"` + id + `"}`),
+			Tags:          map[string]string{"env": "prod"},
+		})
+	}
+	return inv
+}
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	inv := newStoreInventory(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), "logs", "assets")
+	inv.Incomplete = true
+	inv.Mismatches = []CountMismatch{{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Expected: 3, Collected: 2}}
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-1", Region: "us-west-2", Source: "dev.json"})
+
+	id, err := s.Save(ctx, inv)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := s.Load(ctx, id)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, inv) {
+		t.Errorf("Load() = %+v\nwant %+v", got, inv)
+	}
+}
+
+func TestOpenStore_AddsSourceColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.sqlite")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	s.Close()
+
+	// Recreate a store from before resources had a source column.
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	if _, err := db.Exec(`ALTER TABLE resources DROP COLUMN source`); err != nil {
+		t.Fatalf("dropping source column: %v", err)
+	}
+	db.Close()
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() on old store error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	inv := newStoreInventory(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), "logs")
+	inv.Resources[0].Source = "prod.json"
+	id, err := s.Save(ctx, inv)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := s.Load(ctx, id)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.Resources[0].Source != "prod.json" {
+		t.Errorf("Source = %q, want %q", got.Resources[0].Source, "prod.json")
+	}
+}
+
+func TestStore_LoadUnknown(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.Load(context.Background(), 42); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Load() error = %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+func TestStore_SnapshotsAndLatest(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.LatestID(ctx); !errors.Is(err, ErrNoSnapshots) {
+		t.Errorf("LatestID() on empty store error = %v, want ErrNoSnapshots", err)
+	}
+
+	day := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	newer, err := s.Save(ctx, newStoreInventory(day.Add(24*time.Hour), "logs", "assets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(ctx, newStoreInventory(day, "logs")); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := s.Snapshots(ctx)
+	if err != nil {
+		t.Fatalf("Snapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Snapshots() returned %d, want 2", len(snapshots))
+	}
+	if !snapshots[0].CollectedAt.Equal(day) || snapshots[0].ResourceCount != 1 {
+		t.Errorf("Snapshots()[0] = %+v, want the older snapshot first", snapshots[0])
+	}
+	if len(snapshots[1].Regions) != 2 || snapshots[1].Profile != "prod" {
+		t.Errorf("Snapshots()[1] = %+v", snapshots[1])
+	}
+
+	latest, err := s.LatestID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != newer {
+		t.Errorf("LatestID() = %d, want %d", latest, newer)
+	}
+}
+
+func TestStore_QueryAcrossSnapshots(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	day := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i, ids := range [][]string{{"logs"}, {"logs", "assets"}, {"logs", "assets"}} {
+		if _, err := s.Save(ctx, newStoreInventory(day.Add(time.Duration(i)*24*time.Hour), ids...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := s.Query(ctx, `SELECT MIN(s.collected_at) AS first_seen FROM resources r
+		JOIN snapshots s ON s.id = r.snapshot_id WHERE r.arn = ?`, "arn:aws:s3:::assets")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result.Columns) != 1 || result.Columns[0] != "first_seen" {
+		t.Errorf("Query() columns = %v", result.Columns)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][0] != "2024-01-16T10:00:00.000000000Z" {
+		t.Errorf("Query() rows = %v, want first seen on 2024-01-16", result.Rows)
+	}
+
+	result, err = s.Query(ctx, `SELECT json_extract(tags, '$.env'), COUNT(*) FROM resources GROUP BY 1`)
+	if err != nil {
+		t.Fatalf("Query() with json_extract error = %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][0] != "prod" || result.Rows[0][1] != "5" {
+		t.Errorf("Query() rows = %v, want [[prod 5]]", result.Rows)
+	}
+}
+
+func TestStore_QueryIsReadOnly(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	if _, err := s.Save(ctx, newStoreInventory(time.Now().UTC(), "logs")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Query(ctx, `DELETE FROM resources`); err == nil {
+		t.Error("Query() should reject statements that modify the store")
+	}
+	if _, err := s.Query(ctx, `PRAGMA query_only = OFF; DELETE FROM resources; SELECT 1`); err == nil {
+		t.Error("Query() should reject statements that modify the store after turning off query_only")
+	}
+	// Settings from one query must not let the next one write.
+	other := filepath.Join(t.TempDir(), "other.sqlite")
+	s.Query(ctx, `PRAGMA query_only = OFF`)
+	s.Query(ctx, `ATTACH DATABASE '`+other+`' AS other`)
+	if _, err := s.Query(ctx, `CREATE TABLE other.t (x)`); err == nil {
+		t.Error("Query() should not write through a database attached by an earlier query")
+	}
+
+	// The connection must be writable again afterwards.
+	if _, err := s.Save(ctx, newStoreInventory(time.Now().UTC(), "assets")); err != nil {
+		t.Errorf("Save() after Query() error = %v", err)
+	}
+	result, err := s.Query(ctx, `SELECT COUNT(*) FROM resources`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows[0][0] != "2" {
+		t.Errorf("resources after rejected DELETE = %s, want 2", result.Rows[0][0])
+	}
+}
+
+func TestStore_QueryRejectsMultipleStatements(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	if _, err := s.Save(ctx, newStoreInventory(time.Now().UTC(), "logs")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.Query(ctx, `PRAGMA query_only = OFF; DELETE FROM resources; SELECT 1`)
+	if !errors.Is(err, ErrMultipleStatements) {
+		t.Errorf("Query() error = %v, want ErrMultipleStatements", err)
+	}
+	result, err := s.Query(ctx, `SELECT COUNT(*) FROM resources`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows[0][0] != "1" {
+		t.Errorf("resources after rejected DELETE = %s, want 1", result.Rows[0][0])
+	}
+}
+
+func TestHasMultipleStatements(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`SELECT 1`, false},
+		{`SELECT 1;`, false},
+		{"SELECT 1; -- done\n", false},
+		{`SELECT 1; /* done */`, false},
+		{`SELECT ';' AS a, "b;" FROM [c;d]`, false},
+		{`SELECT 'it''s; fine'`, false},
+		{"SELECT 1 -- ; DELETE FROM resources", false},
+		{`SELECT 1; SELECT 2`, true},
+		{`PRAGMA query_only = OFF; DELETE FROM resources; SELECT 1`, true},
+		{`SELECT 1 /* ; */; DELETE FROM resources`, true},
+		{`SELECT 'a''b'; DELETE FROM resources`, true},
+	}
+	for _, tt := range tests {
+		if got := hasMultipleStatements(tt.query); got != tt.want {
+			t.Errorf("hasMultipleStatements(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package awsassetinventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// ErrCountsOnlyTags is returned by TagPolicy.Evaluate for a counts-only
+// inventory, which has no resources or tags to check.
+var ErrCountsOnlyTags = errors.New("cannot check tags of a counts-only inventory")
+
+// ErrNoTags is returned by TagPolicy.Evaluate for an inventory in which no
+// resource has tags, such as one collected before tags were, or without
+// permission to query them, where every resource would otherwise fail.
+var ErrNoTags = errors.New("no resource in the inventory has tags; collect it again with config:SelectResourceConfig permission")
+
+// TagRule lists the tags a resource must carry and the values its tags may
+// have. Allowed maps a tag key to a regular expression that the whole tag
+// value must match; it applies whenever the tag is present, required or not.
+type TagRule struct {
+	Required []string          `json:"required,omitempty"`
+	Allowed  map[string]string `json:"allowed,omitempty"`
+}
+
+// TagPolicy is a set of tag rules keyed by resource type or
+// AllResourceTypes. A resource must satisfy both the AllResourceTypes rule
+// and the rule for its own type. Resource types in Exclude, which typically
+// cannot be tagged, are not checked at all. Tag keys are case-sensitive.
+//
+// Policy files use the same shape:
+//
+//	{
+//	  "rules": {
+//	    "*": {"required": ["owner", "env"], "allowed": {"env": "prod|staging|dev"}},
+//	    "AWS::EC2::Instance": {"required": ["cost-center"], "allowed": {"cost-center": "cc-[0-9]{4}"}}
+//	  },
+//	  "exclude": ["AWS::Config::ResourceCompliance"]
+//	}
+type TagPolicy struct {
+	Rules   map[string]TagRule `json:"rules"`
+	Exclude []ResourceType     `json:"exclude,omitempty"`
+}
+
+// LoadTagPolicy reads a tag policy from a JSON file and checks that its
+// patterns are valid regular expressions.
+func LoadTagPolicy(path string) (*TagPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy TagPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if _, err := policy.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// Tag violation reasons.
+const (
+	TagMissing = "missing"
+	TagInvalid = "invalid"
+)
+
+// TagViolation is a single way a resource breaks a tag policy: a required
+// tag that is missing, or a tag whose Value does not match Pattern.
+type TagViolation struct {
+	Key     string `json:"key"`
+	Reason  string `json:"reason"`
+	Value   string `json:"value,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// String describes the violation, for example `missing "owner"`.
+func (v TagViolation) String() string {
+	if v.Reason == TagMissing {
+		return fmt.Sprintf("missing %q", v.Key)
+	}
+	return fmt.Sprintf("%q is %q, want %s", v.Key, v.Value, v.Pattern)
+}
+
+// NonCompliantResource is a resource that breaks a tag policy.
+type NonCompliantResource struct {
+	Resource   Resource       `json:"resource"`
+	Violations []TagViolation `json:"violations"`
+}
+
+// TagComplianceCounts is the number of resources checked against a tag
+// policy and how many of them broke it.
+type TagComplianceCounts struct {
+	Evaluated    int     `json:"evaluated"`
+	NonCompliant int     `json:"nonCompliant"`
+	Percent      float64 `json:"compliancePercent"`
+}
+
+func (c *TagComplianceCounts) add(compliant bool) {
+	c.Evaluated++
+	if !compliant {
+		c.NonCompliant++
+	}
+	c.Percent = compliancePercent(c.Evaluated, c.NonCompliant)
+}
+
+// compliancePercent is the share of evaluated resources that are
+// compliant; it is 100 when nothing was evaluated.
+func compliancePercent(evaluated, nonCompliant int) float64 {
+	if evaluated == 0 {
+		return 100
+	}
+	return float64(evaluated-nonCompliant) * 100 / float64(evaluated)
+}
+
+// TagCompliance is the result of checking an inventory against a tag
+// policy. Resources lists the non-compliant resources sorted by type,
+// region, account and ID.
+type TagCompliance struct {
+	CollectedAt time.Time `json:"collectedAt"`
+	Profile     string    `json:"profile"`
+	TagComplianceCounts
+	ByType    map[ResourceType]TagComplianceCounts `json:"byType"`
+	ByRegion  map[Region]TagComplianceCounts       `json:"byRegion"`
+	ByAccount map[string]TagComplianceCounts       `json:"byAccount"`
+	Resources []NonCompliantResource               `json:"resources"`
+}
+
+// ToJSON serializes the compliance result to JSON.
+func (c *TagCompliance) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// compiledTagRule is a TagRule with its patterns compiled.
+type compiledTagRule struct {
+	required []string
+	allowed  map[string]*regexp.Regexp
+	patterns map[string]string
+}
+
+func (p *TagPolicy) compile() (map[string]compiledTagRule, error) {
+	compiled := make(map[string]compiledTagRule, len(p.Rules))
+	for rt, rule := range p.Rules {
+		cr := compiledTagRule{
+			required: rule.Required,
+			allowed:  make(map[string]*regexp.Regexp, len(rule.Allowed)),
+			patterns: rule.Allowed,
+		}
+		for key, pattern := range rule.Allowed {
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for tag %q of %s: %w", key, rt, err)
+			}
+			cr.allowed[key] = re
+		}
+		compiled[rt] = cr
+	}
+	return compiled, nil
+}
+
+// Evaluate checks every resource in inv that the policy applies to.
+func (p *TagPolicy) Evaluate(inv *Inventory) (*TagCompliance, error) {
+	if inv.CountsOnly {
+		return nil, ErrCountsOnlyTags
+	}
+	if len(inv.Resources) > 0 && !inv.HasTags() {
+		return nil, ErrNoTags
+	}
+	rules, err := p.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[ResourceType]bool, len(p.Exclude))
+	for _, rt := range p.Exclude {
+		excluded[rt] = true
+	}
+
+	c := &TagCompliance{
+		CollectedAt:         inv.CollectedAt,
+		Profile:             inv.Profile,
+		TagComplianceCounts: TagComplianceCounts{Percent: 100},
+		ByType:              make(map[ResourceType]TagComplianceCounts),
+		ByRegion:            make(map[Region]TagComplianceCounts),
+		ByAccount:           make(map[string]TagComplianceCounts),
+		Resources:           make([]NonCompliantResource, 0),
+	}
+	for _, r := range inv.Resources {
+		if excluded[r.ResourceType] {
+			continue
+		}
+		general, hasGeneral := rules[AllResourceTypes]
+		specific, hasSpecific := rules[r.ResourceType.String()]
+		if !hasGeneral && !hasSpecific {
+			continue
+		}
+
+		var violations []TagViolation
+		seen := make(map[TagViolation]bool)
+		for _, rule := range []compiledTagRule{general, specific} {
+			for _, v := range rule.check(r.Tags) {
+				// A tag both rules check the same way is only reported once.
+				if seen[v] {
+					continue
+				}
+				seen[v] = true
+				violations = append(violations, v)
+			}
+		}
+		compliant := len(violations) == 0
+		if !compliant {
+			c.Resources = append(c.Resources, NonCompliantResource{Resource: r, Violations: violations})
+		}
+
+		c.TagComplianceCounts.add(compliant)
+		addTagCount(c.ByType, r.ResourceType, compliant)
+		addTagCount(c.ByRegion, r.Region, compliant)
+		addTagCount(c.ByAccount, r.AccountID, compliant)
+	}
+
+	sort.Slice(c.Resources, func(i, j int) bool {
+		a, b := c.Resources[i].Resource, c.Resources[j].Resource
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.ResourceID < b.ResourceID
+	})
+	return c, nil
+}
+
+func addTagCount[K comparable](counts map[K]TagComplianceCounts, key K, compliant bool) {
+	c := counts[key]
+	c.add(compliant)
+	counts[key] = c
+}
+
+// check returns the ways tags break the rule: required keys first, in
+// policy order, then disallowed values sorted by key.
+func (rule compiledTagRule) check(tags map[string]string) []TagViolation {
+	var violations []TagViolation
+	for _, key := range rule.required {
+		if _, ok := tags[key]; !ok {
+			violations = append(violations, TagViolation{Key: key, Reason: TagMissing})
+		}
+	}
+
+	keys := make([]string, 0, len(rule.allowed))
+	for key := range rule.allowed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := tags[key]
+		if ok && !rule.allowed[key].MatchString(value) {
+			violations = append(violations, TagViolation{Key: key, Reason: TagInvalid, Value: value, Pattern: rule.patterns[key]})
+		}
+	}
+	return violations
+}
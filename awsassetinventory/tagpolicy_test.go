@@ -0,0 +1,165 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func tagTestInventory() *Inventory {
+	inv := NewInventory("prod", []Region{"us-east-1", "us-west-2"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1", AccountID: "111111111111",
+		Tags: map[string]string{"owner": "web-team", "env": "prod", "cost-center": "cc-1234"}})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2", AccountID: "222222222222",
+		Tags: map[string]string{"owner": "data", "env": "qa"}})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1", AccountID: "111111111111",
+		Tags: map[string]string{"owner": "ops", "env": "dev"}})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "scratch", Region: "us-east-1", AccountID: "111111111111"})
+	inv.AddResource(Resource{ResourceType: "AWS::Config::ResourceCompliance", ResourceID: "rc-1", Region: "us-east-1", AccountID: "111111111111"})
+	return inv
+}
+
+func tagTestPolicy() *TagPolicy {
+	return &TagPolicy{
+		Rules: map[string]TagRule{
+			AllResourceTypes:     {Required: []string{"owner", "env"}, Allowed: map[string]string{"env": "prod|staging|dev"}},
+			"AWS::EC2::Instance": {Required: []string{"owner", "cost-center"}, Allowed: map[string]string{"cost-center": "cc-[0-9]{4}"}},
+		},
+		Exclude: []ResourceType{"AWS::Config::ResourceCompliance"},
+	}
+}
+
+func TestTagPolicy_Evaluate(t *testing.T) {
+	c, err := tagTestPolicy().Evaluate(tagTestInventory())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if c.Evaluated != 4 || c.NonCompliant != 2 || c.Percent != 50 {
+		t.Errorf("totals = %+v, want 4 evaluated, 2 non-compliant, 50%%", c.TagComplianceCounts)
+	}
+	if got := c.ByType["AWS::S3::Bucket"]; got.Evaluated != 2 || got.NonCompliant != 1 {
+		t.Errorf("ByType[AWS::S3::Bucket] = %+v", got)
+	}
+	if got := c.ByRegion["us-west-2"]; got.Evaluated != 1 || got.NonCompliant != 1 || got.Percent != 0 {
+		t.Errorf("ByRegion[us-west-2] = %+v", got)
+	}
+	if got := c.ByAccount["111111111111"]; got.Evaluated != 3 || got.NonCompliant != 1 {
+		t.Errorf("ByAccount[111111111111] = %+v", got)
+	}
+
+	if len(c.Resources) != 2 {
+		t.Fatalf("Resources = %+v, want 2", c.Resources)
+	}
+	i2 := c.Resources[0]
+	if i2.Resource.ResourceID != "i-2" {
+		t.Errorf("Resources[0] = %s, want i-2 (sorted by type first)", i2.Resource.ResourceID)
+	}
+	wantViolations := []TagViolation{
+		{Key: "env", Reason: TagInvalid, Value: "qa", Pattern: "prod|staging|dev"},
+		{Key: "cost-center", Reason: TagMissing},
+	}
+	if !reflect.DeepEqual(i2.Violations, wantViolations) {
+		t.Errorf("i-2 violations = %+v, want %+v", i2.Violations, wantViolations)
+	}
+	if got := c.Resources[1].Violations; len(got) != 2 || got[0].String() != `missing "owner"` {
+		t.Errorf("scratch violations = %v", got)
+	}
+}
+
+func TestTagPolicy_WholeValueMatch(t *testing.T) {
+	policy := &TagPolicy{Rules: map[string]TagRule{AllResourceTypes: {Allowed: map[string]string{"env": "prod"}}}}
+	inv := NewInventory("p", nil)
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "b", Tags: map[string]string{"env": "preprod"}})
+
+	c, err := policy.Evaluate(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.NonCompliant != 1 {
+		t.Error("allowed patterns should match the whole tag value")
+	}
+}
+
+func TestTagPolicy_EvaluateErrors(t *testing.T) {
+	if _, err := tagTestPolicy().Evaluate(&Inventory{CountsOnly: true}); !errors.Is(err, ErrCountsOnlyTags) {
+		t.Errorf("Evaluate() on counts-only inventory error = %v, want ErrCountsOnlyTags", err)
+	}
+
+	untagged := NewInventory("prod", []Region{"us-east-1"})
+	untagged.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+	if _, err := tagTestPolicy().Evaluate(untagged); !errors.Is(err, ErrNoTags) {
+		t.Errorf("Evaluate() on inventory without tags error = %v, want ErrNoTags", err)
+	}
+
+	bad := &TagPolicy{Rules: map[string]TagRule{AllResourceTypes: {Allowed: map[string]string{"env": "("}}}}
+	if _, err := bad.Evaluate(tagTestInventory()); err == nil {
+		t.Error("Evaluate() should reject an invalid pattern")
+	}
+}
+
+func TestLoadTagPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(path, []byte(`{"rules":{"*":{"required":["owner"]}},"exclude":["AWS::Config::ResourceCompliance"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadTagPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadTagPolicy() error = %v", err)
+	}
+	if len(policy.Rules[AllResourceTypes].Required) != 1 || len(policy.Exclude) != 1 {
+		t.Errorf("LoadTagPolicy() = %+v", policy)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"rules":{"*":{"allowed":{"env":"[a-"}}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTagPolicy(invalid); err == nil || !strings.Contains(err.Error(), `tag "env"`) {
+		t.Errorf("LoadTagPolicy() error = %v, want an invalid pattern error", err)
+	}
+}
+
+func TestTagReportGenerator_Generate(t *testing.T) {
+	c, err := tagTestPolicy().Evaluate(tagTestInventory())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewTagReportGenerator(c).Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"# AWS Asset Inventory Tag Compliance",
+		"**Compliance:** 50.0%",
+		"## By Resource Type\n\n| Resource Type | Evaluated | Non-Compliant | Compliance |\n|---------------|",
+		"| AWS::EC2::Instance | 2 | 1 | 50.0% |",
+		"## By Region",
+		"| us-west-2 | 1 | 1 | 0.0% |",
+		"## By Account",
+		"| 111111111111 | 3 | 1 | 66.6% |",
+		"### AWS::EC2::Instance (1)",
+		`| us-west-2 | 222222222222 | - | i-2 | "env" is "qa", want prod\|staging\|dev; missing "cost-center" |`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	if got := formatPercent(99.96); got != "99.9%" {
+		t.Errorf("formatPercent(99.96) = %s, want 99.9%%", got)
+	}
+	if got := formatPercent(100); got != "100.0%" {
+		t.Errorf("formatPercent(100) = %s", got)
+	}
+}
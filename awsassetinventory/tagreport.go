@@ -0,0 +1,211 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// TagReportGenerator generates markdown tag compliance reports from a
+// TagCompliance, in the same layout as ReportGenerator.
+type TagReportGenerator struct {
+	compliance *TagCompliance
+}
+
+// NewTagReportGenerator creates a new TagReportGenerator for the given result.
+func NewTagReportGenerator(c *TagCompliance) *TagReportGenerator {
+	return &TagReportGenerator{compliance: c}
+}
+
+// Generate writes a complete markdown tag compliance report to the provided
+// writer.
+func (tg *TagReportGenerator) Generate(w io.Writer) error {
+	if err := tg.writeHeader(w); err != nil {
+		return err
+	}
+	if tg.compliance.Evaluated == 0 {
+		_, err := fmt.Fprintf(w, "No resources are covered by the tag policy.\n")
+		return err
+	}
+
+	types := make([]ResourceType, 0, len(tg.compliance.ByType))
+	for rt := range tg.compliance.ByType {
+		types = append(types, rt)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	typeRows := make([]string, len(types))
+	typeCounts := make([]TagComplianceCounts, len(types))
+	for i, rt := range types {
+		typeRows[i] = rt.String()
+		typeCounts[i] = tg.compliance.ByType[rt]
+	}
+	if err := tg.writeCounts(w, "By Resource Type", "Resource Type", typeRows, typeCounts); err != nil {
+		return err
+	}
+
+	regions := make([]Region, 0, len(tg.compliance.ByRegion))
+	for r := range tg.compliance.ByRegion {
+		regions = append(regions, r)
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i] < regions[j] })
+	regionRows := make([]string, len(regions))
+	regionCounts := make([]TagComplianceCounts, len(regions))
+	for i, r := range regions {
+		regionRows[i] = r.String()
+		regionCounts[i] = tg.compliance.ByRegion[r]
+	}
+	if err := tg.writeCounts(w, "By Region", "Region", regionRows, regionCounts); err != nil {
+		return err
+	}
+
+	accounts := make([]string, 0, len(tg.compliance.ByAccount))
+	for a := range tg.compliance.ByAccount {
+		accounts = append(accounts, a)
+	}
+	sort.Strings(accounts)
+	accountRows := make([]string, len(accounts))
+	accountCounts := make([]TagComplianceCounts, len(accounts))
+	for i, a := range accounts {
+		accountRows[i] = a
+		if a == "" {
+			accountRows[i] = "-"
+		}
+		accountCounts[i] = tg.compliance.ByAccount[a]
+	}
+	if err := tg.writeCounts(w, "By Account", "Account", accountRows, accountCounts); err != nil {
+		return err
+	}
+
+	return tg.writeNonCompliant(w)
+}
+
+func (tg *TagReportGenerator) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# AWS Asset Inventory Tag Compliance\n\n")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Collected:** %s\n", tg.compliance.CollectedAt.Format("2006-01-02 15:04:05 UTC"))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Profile:** %s\n", tg.compliance.Profile)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Resources Evaluated:** %d\n", tg.compliance.Evaluated)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Non-Compliant:** %d\n", tg.compliance.NonCompliant)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "**Compliance:** %s\n\n", formatPercent(tg.compliance.Percent))
+	return err
+}
+
+func (tg *TagReportGenerator) writeCounts(w io.Writer, title, column string, rows []string, counts []TagComplianceCounts) error {
+	_, err := fmt.Fprintf(w, "## %s\n\n", title)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "| %s | Evaluated | Non-Compliant | Compliance |\n", column)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "|%s|-----------|---------------|------------|\n", strings.Repeat("-", len(column)+2))
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		c := counts[i]
+		_, err = fmt.Fprintf(w, "| %s | %d | %d | %s |\n", row, c.Evaluated, c.NonCompliant, formatPercent(c.Percent))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
+// writeNonCompliant lists the non-compliant resources in a table per
+// resource type, ordered by region and account.
+func (tg *TagReportGenerator) writeNonCompliant(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "## Non-Compliant Resources\n\n")
+	if err != nil {
+		return err
+	}
+
+	if len(tg.compliance.Resources) == 0 {
+		_, err = fmt.Fprintf(w, "All evaluated resources comply with the tag policy.\n\n")
+		return err
+	}
+
+	resources := tg.compliance.Resources
+	for start := 0; start < len(resources); {
+		rt := resources[start].Resource.ResourceType
+		end := start
+		for end < len(resources) && resources[end].Resource.ResourceType == rt {
+			end++
+		}
+
+		_, err = fmt.Fprintf(w, "### %s (%d)\n\n", rt, end-start)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "| Region | Account | Name | ID | Violations |\n")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "|--------|---------|------|----|------------|\n")
+		if err != nil {
+			return err
+		}
+
+		for _, nc := range resources[start:end] {
+			r := nc.Resource
+			name := r.ResourceName
+			if name == "" {
+				name = "-"
+			}
+			account := r.AccountID
+			if account == "" {
+				account = "-"
+			}
+			violations := make([]string, len(nc.Violations))
+			for i, v := range nc.Violations {
+				violations[i] = v.String()
+			}
+			_, err = fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+				r.Region,
+				account,
+				escapeMarkdown(name),
+				escapeMarkdown(r.ResourceID),
+				escapeMarkdown(strings.Join(violations, "; ")))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(w, "\n")
+		if err != nil {
+			return err
+		}
+		start = end
+	}
+
+	return nil
+}
+
+// formatPercent formats a compliance percentage with one decimal place,
+// rounding down so that anything short of full compliance never shows as
+// 100.0%.
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%.1f%%", math.Floor(p*10)/10)
+}
@@ -0,0 +1,119 @@
+package awsassetinventory
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the OpenTelemetry instrumentation scope for spans
+// and metrics produced by this package.
+const instrumentationName = "github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+
+// instruments holds the metric instruments recorded during collection.
+type instruments struct {
+	resourcesCollected metric.Int64Counter
+	callDuration       metric.Float64Histogram
+	throttles          metric.Int64Counter
+}
+
+func (c *Collector) tracer() trace.Tracer {
+	tp := c.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(instrumentationName)
+}
+
+func (c *Collector) metrics() *instruments {
+	c.instrumentsOnce.Do(func() {
+		mp := c.MeterProvider
+		if mp == nil {
+			mp = otel.GetMeterProvider()
+		}
+		meter := mp.Meter(instrumentationName)
+
+		inst := &instruments{}
+		var err error
+		inst.resourcesCollected, err = meter.Int64Counter("asset_inventory.resources.collected",
+			metric.WithDescription("Resources collected from AWS Config"),
+			metric.WithUnit("{resource}"))
+		if err != nil {
+			otel.Handle(err)
+			inst.resourcesCollected = noop.Int64Counter{}
+		}
+		inst.callDuration, err = meter.Float64Histogram("asset_inventory.api.duration",
+			metric.WithDescription("Duration of AWS Config API calls, per attempt"),
+			metric.WithUnit("s"))
+		if err != nil {
+			otel.Handle(err)
+			inst.callDuration = noop.Float64Histogram{}
+		}
+		inst.throttles, err = meter.Int64Counter("asset_inventory.api.throttles",
+			metric.WithDescription("AWS Config API calls rejected by throttling"),
+			metric.WithUnit("{call}"))
+		if err != nil {
+			otel.Handle(err)
+			inst.throttles = noop.Int64Counter{}
+		}
+		c.instruments = inst
+	})
+	return c.instruments
+}
+
+// startSpan starts a span for a unit of collection work in region, and for
+// resourceType when it is set.
+func (c *Collector) startSpan(ctx context.Context, name string, region Region, resourceType ResourceType) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("aws.region", region.String())}
+	if resourceType != "" {
+		attrs = append(attrs, attribute.String("aws.config.resource_type", resourceType.String()))
+	}
+	return c.tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *Collector) recordCallDuration(ctx context.Context, operation string, region Region, d time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		if isRetryable(err) {
+			outcome = "throttled"
+		}
+	}
+	c.metrics().callDuration.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attribute.String("aws.operation", operation),
+		attribute.String("aws.region", region.String()),
+		attribute.String("outcome", outcome),
+	))
+}
+
+func (c *Collector) recordThrottle(operation string, region Region) {
+	c.metrics().throttles.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("aws.operation", operation),
+		attribute.String("aws.region", region.String()),
+	))
+}
+
+func (c *Collector) recordResourcesCollected(ctx context.Context, region Region, resourceType ResourceType, n int) {
+	if n == 0 {
+		return
+	}
+	c.metrics().resourcesCollected.Add(ctx, int64(n), metric.WithAttributes(
+		attribute.String("aws.region", region.String()),
+		attribute.String("aws.config.resource_type", resourceType.String()),
+	))
+}
@@ -0,0 +1,174 @@
+package awsassetinventory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTelemetryMock() *mockConfigClient {
+	countCalls := 0
+	return &mockConfigClient{
+		getDiscoveredResourceCountsFunc: func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+			countCalls++
+			if countCalls == 1 {
+				return nil, &awshttp.ResponseError{
+					ResponseError: &smithyhttp.ResponseError{
+						Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 400}},
+						Err:      errors.New("ThrottlingException: Rate exceeded"),
+					},
+					RequestID: "req-1",
+				}
+			}
+			return &configservice.GetDiscoveredResourceCountsOutput{
+				ResourceCounts: []types.ResourceCount{{ResourceType: "AWS::S3::Bucket", Count: 1}},
+			}, nil
+		},
+		listDiscoveredResourcesFunc: func(ctx context.Context, params *configservice.ListDiscoveredResourcesInput, optFns ...func(*configservice.Options)) (*configservice.ListDiscoveredResourcesOutput, error) {
+			return &configservice.ListDiscoveredResourcesOutput{
+				ResourceIdentifiers: []types.ResourceIdentifier{{ResourceId: aws.String("bucket-1")}},
+			}, nil
+		},
+		batchGetResourceConfigFunc: func(ctx context.Context, params *configservice.BatchGetResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.BatchGetResourceConfigOutput, error) {
+			return &configservice.BatchGetResourceConfigOutput{
+				BaseConfigurationItems: []types.BaseConfigurationItem{
+					{ResourceType: "AWS::S3::Bucket", ResourceId: aws.String("bucket-1")},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestCollector_Collect_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	mock := newTelemetryMock()
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	if _, err := c.Collect(context.Background(), []Region{"us-east-1"}); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	byName := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		byName[s.Name()] = append(byName[s.Name()], s)
+	}
+
+	for name, want := range map[string]int{
+		"Collect":                               1,
+		"CollectRegion":                         1,
+		"CollectResourceType":                   1,
+		"AWSConfig.GetDiscoveredResourceCounts": 2,
+		"AWSConfig.ListDiscoveredResources":     1,
+		"AWSConfig.BatchGetResourceConfig":      1,
+	} {
+		if got := len(byName[name]); got != want {
+			t.Errorf("%s spans = %d, want %d", name, got, want)
+		}
+	}
+
+	run := byName["Collect"][0]
+	region := byName["CollectRegion"][0]
+	if region.Parent().SpanID() != run.SpanContext().SpanID() {
+		t.Error("CollectRegion span should be a child of the Collect span")
+	}
+	rt := byName["CollectResourceType"][0]
+	if rt.Parent().SpanID() != region.SpanContext().SpanID() {
+		t.Error("CollectResourceType span should be a child of the CollectRegion span")
+	}
+	if !hasAttr(rt.Attributes(), attribute.String("aws.config.resource_type", "AWS::S3::Bucket")) {
+		t.Errorf("CollectResourceType attributes = %v, want resource type", rt.Attributes())
+	}
+
+	throttled := byName["AWSConfig.GetDiscoveredResourceCounts"][0]
+	for _, want := range []attribute.KeyValue{
+		attribute.Int("retry.attempt", 1),
+		attribute.String("aws.request_id", "req-1"),
+	} {
+		if !hasAttr(throttled.Attributes(), want) {
+			t.Errorf("throttled call attributes = %v, want %v", throttled.Attributes(), want)
+		}
+	}
+	if len(throttled.Events()) == 0 {
+		t.Error("throttled call span should record the error")
+	}
+	if !hasAttr(byName["AWSConfig.GetDiscoveredResourceCounts"][1].Attributes(), attribute.Int("retry.attempt", 2)) {
+		t.Error("retried call span should have retry.attempt 2")
+	}
+}
+
+func TestCollector_Collect_Metrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mock := newTelemetryMock()
+	c := NewCollector("test", func(r Region) ConfigClient { return mock })
+	c.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	if _, err := c.Collect(context.Background(), []Region{"us-east-1"}); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("reader.Collect() error = %v", err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	if got := sumInt64(t, metrics["asset_inventory.resources.collected"]); got != 1 {
+		t.Errorf("resources.collected = %d, want 1", got)
+	}
+	if got := sumInt64(t, metrics["asset_inventory.api.throttles"]); got != 1 {
+		t.Errorf("api.throttles = %d, want 1", got)
+	}
+
+	hist, ok := metrics["asset_inventory.api.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("api.duration = %T, want histogram", metrics["asset_inventory.api.duration"])
+	}
+	var calls uint64
+	for _, dp := range hist.DataPoints {
+		calls += dp.Count
+	}
+	if calls != 4 {
+		t.Errorf("api.duration recorded %d calls, want 4", calls)
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+func sumInt64(t *testing.T, data metricdata.Aggregation) int64 {
+	t.Helper()
+	sum, ok := data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("metric = %T, want int64 sum", data)
+	}
+	var total int64
+	for _, dp := range sum.DataPoints {
+		total += dp.Value
+	}
+	return total
+}
@@ -0,0 +1,105 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// TextRenderer renders a report as plain text with aligned columns, for
+// terminals and logs.
+type TextRenderer struct{}
+
+// Render writes the text report of inv to w.
+func (TextRenderer) Render(w io.Writer, inv *Inventory, opts RenderOptions) error {
+	tw := &textReportWriter{tw: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}
+
+	regionStrings := make([]string, len(inv.Regions))
+	for i, r := range inv.Regions {
+		regionStrings[i] = r.String()
+	}
+	tw.printf("AWS Asset Inventory Report\n\n")
+	tw.printf("Collected:\t%s\n", inv.CollectedAt.Format("2006-01-02 15:04:05 UTC"))
+	tw.printf("Profile:\t%s\n", inv.Profile)
+	tw.printf("Regions:\t%s\n", strings.Join(regionStrings, ", "))
+	if inv.Incomplete {
+		tw.printf("Status:\tIncomplete (collection failed in some regions or was interrupted)\n")
+	}
+	if inv.CountsOnly {
+		tw.printf("Mode:\tCounts only\n")
+	}
+	tw.printf("Total Resources:\t%d\n", inv.ResourceCount())
+	tw.flush()
+
+	counts := inv.ResourceCountByType()
+	tw.printf("\nRESOURCE TYPE\tCOUNT\n")
+	for _, rt := range sortedResourceTypes(counts) {
+		tw.printf("%s\t%d\n", rt, counts[rt])
+	}
+	tw.flush()
+
+	countsByRegion := inv.ResourceCountByTypeAndRegion()
+	tw.printf("\nREGION\tRESOURCE TYPE\tCOUNT\n")
+	for _, region := range sortedRegions(countsByRegion) {
+		typeCounts := countsByRegion[region]
+		for _, rt := range sortedResourceTypes(typeCounts) {
+			tw.printf("%s\t%s\t%d\n", region, rt, typeCounts[rt])
+		}
+	}
+	tw.flush()
+
+	if len(inv.Mismatches) > 0 {
+		tw.printf("\nRECONCILIATION\n")
+		tw.printf("REGION\tRESOURCE TYPE\tEXPECTED\tCOLLECTED\n")
+		for _, m := range inv.Mismatches {
+			tw.printf("%s\t%s\t%d\t%d\n", m.Region, m.ResourceType, m.Expected, m.Collected)
+		}
+		tw.flush()
+	}
+
+	if opts.IncludeDetails {
+		tw.printf("\nRESOURCE DETAILS\n")
+		if inv.CountsOnly {
+			tw.printf("Resource details are not available for counts-only inventories.\n")
+		} else {
+			resources := make([]Resource, len(inv.Resources))
+			copy(resources, inv.Resources)
+			SortByTypeRegionID(resources)
+			tw.printf("RESOURCE TYPE\tREGION\tID\tNAME\n")
+			for _, r := range resources {
+				tw.printf("%s\t%s\t%s\t%s\n", r.ResourceType, r.Region, textCell(r.ResourceID), textCell(r.ResourceName))
+			}
+		}
+		tw.flush()
+	}
+	return tw.err
+}
+
+// textReportWriter writes through a tabwriter and keeps the first error,
+// so a report can be written without checking every line.
+type textReportWriter struct {
+	tw  *tabwriter.Writer
+	err error
+}
+
+func (t *textReportWriter) printf(format string, args ...any) {
+	if t.err == nil {
+		_, t.err = fmt.Fprintf(t.tw, format, args...)
+	}
+}
+
+// flush aligns the columns written since the last flush.
+func (t *textReportWriter) flush() {
+	if t.err == nil {
+		t.err = t.tw.Flush()
+	}
+}
+
+// textCell makes s fit a single tab-separated cell, with "-" for empty.
+func textCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+}
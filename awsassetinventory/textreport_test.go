@@ -0,0 +1,67 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextRenderer(t *testing.T) {
+	inv := htmlTestInventory()
+	inv.Mismatches = []CountMismatch{{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Expected: 2, Collected: 1}}
+
+	var buf bytes.Buffer
+	if err := (TextRenderer{}).Render(&buf, inv, RenderOptions{IncludeDetails: true}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `AWS Asset Inventory Report
+
+Collected:        2026-01-07 15:30:00 UTC
+Profile:          prod
+Regions:          us-east-1, us-west-2
+Status:           Incomplete (collection failed in some regions or was interrupted)
+Total Resources:  4
+
+RESOURCE TYPE       COUNT
+AWS::EC2::Instance  3
+AWS::S3::Bucket     1
+
+REGION     RESOURCE TYPE       COUNT
+us-east-1  AWS::EC2::Instance  2
+us-east-1  AWS::S3::Bucket     1
+us-west-2  AWS::EC2::Instance  1
+
+RECONCILIATION
+REGION     RESOURCE TYPE    EXPECTED  COLLECTED
+us-east-1  AWS::S3::Bucket  2         1
+
+RESOURCE DETAILS
+RESOURCE TYPE       REGION     ID    NAME
+AWS::EC2::Instance  us-east-1  i-1   <script>alert(1)</script>
+AWS::EC2::Instance  us-east-1  i-3   -
+AWS::EC2::Instance  us-west-2  i-2   -
+AWS::S3::Bucket     us-east-1  logs  -
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTextRenderer_CountsOnly(t *testing.T) {
+	inv := &Inventory{
+		Profile:    "prod",
+		CountsOnly: true,
+		Counts:     []ResourceTypeCount{{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Count: 7}},
+	}
+
+	var buf bytes.Buffer
+	if err := (TextRenderer{}).Render(&buf, inv, RenderOptions{IncludeDetails: true}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"Mode:             Counts only", "AWS::S3::Bucket  7", "not available for counts-only"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text report should contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
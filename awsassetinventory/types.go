@@ -2,7 +2,9 @@ package awsassetinventory
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"sort"
 	"time"
 )
 
@@ -40,14 +42,110 @@ type Resource struct {
 	ARN              string            `json:"arn,omitempty"`
 	Configuration    json.RawMessage   `json:"configuration,omitempty"`
 	Tags             map[string]string `json:"tags,omitempty"`
+	// Source names the MergeSource a merged inventory's resource came from.
+	// Stores keep it in the resources table's source column.
+	Source string `json:"source,omitempty"`
+}
+
+// Key identifies the resource across inventories: its ARN, or its type,
+// region and ID when it has no ARN.
+func (r Resource) Key() string {
+	if r.ARN != "" {
+		return r.ARN
+	}
+	return r.idKey()
+}
+
+// idKey identifies the resource by its type, region and ID.
+func (r Resource) idKey() string {
+	return fmt.Sprintf("%s/%s/%s", r.ResourceType, r.Region, r.ResourceID)
+}
+
+// nonZonalAvailabilityZones are the values AWS Config reports as the
+// availability zone of resources that are not placed in a single one.
+var nonZonalAvailabilityZones = map[string]bool{
+	"Regional":                    true,
+	"Not Applicable":              true,
+	"Multiple Availability Zones": true,
+}
+
+// zonalAvailabilityZone returns az, or "" when it is one of the values AWS
+// Config uses for resources that are not in a single availability zone.
+func zonalAvailabilityZone(az string) string {
+	if nonZonalAvailabilityZones[az] {
+		return ""
+	}
+	return az
+}
+
+// ResourceTypeCount is the number of resources of a single type that AWS Config
+// reports as discovered in a region.
+type ResourceTypeCount struct {
+	ResourceType ResourceType `json:"resourceType"`
+	Region       Region       `json:"awsRegion"`
+	Count        int          `json:"count"`
+}
+
+// CountMismatch records a resource type in a region where the number of
+// resources collected differs from the count AWS Config reported.
+type CountMismatch struct {
+	ResourceType ResourceType `json:"resourceType"`
+	Region       Region       `json:"awsRegion"`
+	Expected     int          `json:"expected"`
+	Collected    int          `json:"collected"`
+}
+
+// Region collection statuses.
+const (
+	RegionCollected = "collected"
+	RegionFailed    = "failed"
+)
+
+// RegionStatus is the outcome of collecting one region: how many resources
+// were gathered and, when it failed, the error.
+type RegionStatus struct {
+	Region    Region `json:"awsRegion"`
+	Status    string `json:"status"`
+	Resources int    `json:"resources"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ResourceFailure is a resource that was discovered but whose configuration
+// could not be retrieved. Such resources are kept with only the details AWS
+// Config listed for them, or are missing when it listed none.
+type ResourceFailure struct {
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   string       `json:"resourceId"`
+	Region       Region       `json:"awsRegion"`
+	Reason       string       `json:"reason"`
 }
 
 // Inventory holds the collection of AWS resources discovered across regions.
+//
+// Counts holds the per-type counts AWS Config reported for each successfully
+// collected region. A counts-only inventory carries those counts instead of
+// individual resources; the ResourceCount* methods report from Counts in that case.
+//
+// Incomplete is set when collection failed in some region or was interrupted,
+// so Resources holds only what was gathered before that happened. RegionStatus
+// records how collection went in each region, and Failures the resources whose
+// configuration could not be retrieved; both are empty in inventories written
+// before they were recorded.
+//
+// Sources is set on inventories built by Merge and describes each inventory
+// that went into it.
 type Inventory struct {
-	CollectedAt time.Time  `json:"collectedAt"`
-	Profile     string     `json:"profile"`
-	Regions     []Region   `json:"regions"`
-	Resources   []Resource `json:"resources"`
+	CollectedAt  time.Time           `json:"collectedAt"`
+	Profile      string              `json:"profile"`
+	Regions      []Region            `json:"regions"`
+	Incomplete   bool                `json:"incomplete,omitempty"`
+	CountsOnly   bool                `json:"countsOnly,omitempty"`
+	Counts       []ResourceTypeCount `json:"counts,omitempty"`
+	Mismatches   []CountMismatch     `json:"mismatches,omitempty"`
+	RegionStatus []RegionStatus      `json:"regionStatus,omitempty"`
+	Failures     []ResourceFailure   `json:"failures,omitempty"`
+	Sources      []MergeSource       `json:"sources,omitempty"`
+	Resources    []Resource          `json:"resources"`
 }
 
 // NewInventory creates a new Inventory with the given profile and regions.
@@ -65,14 +163,49 @@ func (inv *Inventory) AddResource(r Resource) {
 	inv.Resources = append(inv.Resources, r)
 }
 
+// AddCount appends a per-type resource count to the inventory.
+func (inv *Inventory) AddCount(c ResourceTypeCount) {
+	inv.Counts = append(inv.Counts, c)
+}
+
+// FailedRegions returns the status of every region whose collection failed.
+func (inv *Inventory) FailedRegions() []RegionStatus {
+	var failed []RegionStatus
+	for _, s := range inv.RegionStatus {
+		if s.Status == RegionFailed {
+			failed = append(failed, s)
+		}
+	}
+	return failed
+}
+
+// HasCollectionIssues reports whether any region failed or any resource's
+// configuration could not be retrieved.
+func (inv *Inventory) HasCollectionIssues() bool {
+	return inv.Incomplete || len(inv.FailedRegions()) > 0 || len(inv.Failures) > 0
+}
+
 // ResourceCount returns the total number of resources in the inventory.
 func (inv *Inventory) ResourceCount() int {
+	if inv.CountsOnly {
+		total := 0
+		for _, c := range inv.Counts {
+			total += c.Count
+		}
+		return total
+	}
 	return len(inv.Resources)
 }
 
 // ResourceCountByType returns a map of resource type to count.
 func (inv *Inventory) ResourceCountByType() map[ResourceType]int {
 	counts := make(map[ResourceType]int)
+	if inv.CountsOnly {
+		for _, c := range inv.Counts {
+			counts[c.ResourceType] += c.Count
+		}
+		return counts
+	}
 	for _, r := range inv.Resources {
 		counts[r.ResourceType]++
 	}
@@ -82,6 +215,12 @@ func (inv *Inventory) ResourceCountByType() map[ResourceType]int {
 // ResourceCountByRegion returns a map of region to count.
 func (inv *Inventory) ResourceCountByRegion() map[Region]int {
 	counts := make(map[Region]int)
+	if inv.CountsOnly {
+		for _, c := range inv.Counts {
+			counts[c.Region] += c.Count
+		}
+		return counts
+	}
 	for _, r := range inv.Resources {
 		counts[r.Region]++
 	}
@@ -91,6 +230,15 @@ func (inv *Inventory) ResourceCountByRegion() map[Region]int {
 // ResourceCountByTypeAndRegion returns a nested map of region to resource type to count.
 func (inv *Inventory) ResourceCountByTypeAndRegion() map[Region]map[ResourceType]int {
 	counts := make(map[Region]map[ResourceType]int)
+	if inv.CountsOnly {
+		for _, c := range inv.Counts {
+			if counts[c.Region] == nil {
+				counts[c.Region] = make(map[ResourceType]int)
+			}
+			counts[c.Region][c.ResourceType] += c.Count
+		}
+		return counts
+	}
 	for _, r := range inv.Resources {
 		if counts[r.Region] == nil {
 			counts[r.Region] = make(map[ResourceType]int)
@@ -100,6 +248,130 @@ func (inv *Inventory) ResourceCountByTypeAndRegion() map[Region]map[ResourceType
 	return counts
 }
 
+// ResourceCountByAccount returns a map of account ID to count. Counts-only
+// inventories do not record accounts, so the map is empty for them.
+func (inv *Inventory) ResourceCountByAccount() map[string]int {
+	counts := make(map[string]int)
+	if inv.CountsOnly {
+		return counts
+	}
+	for _, r := range inv.Resources {
+		counts[r.AccountID]++
+	}
+	return counts
+}
+
+// ResourceCountByTypeAndAccount returns a nested map of account ID to
+// resource type to count. It is empty for counts-only inventories.
+func (inv *Inventory) ResourceCountByTypeAndAccount() map[string]map[ResourceType]int {
+	counts := make(map[string]map[ResourceType]int)
+	if inv.CountsOnly {
+		return counts
+	}
+	for _, r := range inv.Resources {
+		if counts[r.AccountID] == nil {
+			counts[r.AccountID] = make(map[ResourceType]int)
+		}
+		counts[r.AccountID][r.ResourceType]++
+	}
+	return counts
+}
+
+// ResourceCountByAvailabilityZone returns a nested map of region to
+// availability zone to count. Only resources placed in an availability
+// zone are counted, not those AWS Config reports as Regional, Not
+// Applicable or in Multiple Availability Zones, and it is empty for
+// counts-only inventories.
+func (inv *Inventory) ResourceCountByAvailabilityZone() map[Region]map[string]int {
+	counts := make(map[Region]map[string]int)
+	if inv.CountsOnly {
+		return counts
+	}
+	for _, r := range inv.Resources {
+		az := zonalAvailabilityZone(r.AvailabilityZone)
+		if az == "" {
+			continue
+		}
+		if counts[r.Region] == nil {
+			counts[r.Region] = make(map[string]int)
+		}
+		counts[r.Region][az]++
+	}
+	return counts
+}
+
+// Reconcile compares the counts AWS Config reported against the resources
+// actually collected, for every region that has counts. It returns the
+// mismatches sorted by region and resource type.
+func (inv *Inventory) Reconcile() []CountMismatch {
+	if inv.CountsOnly {
+		return nil
+	}
+
+	expected := make(map[Region]map[ResourceType]int)
+	for _, c := range inv.Counts {
+		if expected[c.Region] == nil {
+			expected[c.Region] = make(map[ResourceType]int)
+		}
+		expected[c.Region][c.ResourceType] += c.Count
+	}
+
+	collected := inv.ResourceCountByTypeAndRegion()
+
+	var mismatches []CountMismatch
+	for region, typeCounts := range expected {
+		seen := make(map[ResourceType]bool)
+		for rt, want := range typeCounts {
+			seen[rt] = true
+			if got := collected[region][rt]; got != want {
+				mismatches = append(mismatches, CountMismatch{ResourceType: rt, Region: region, Expected: want, Collected: got})
+			}
+		}
+		for rt, got := range collected[region] {
+			if !seen[rt] {
+				mismatches = append(mismatches, CountMismatch{ResourceType: rt, Region: region, Expected: 0, Collected: got})
+			}
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Region != mismatches[j].Region {
+			return mismatches[i].Region < mismatches[j].Region
+		}
+		return mismatches[i].ResourceType < mismatches[j].ResourceType
+	})
+	return mismatches
+}
+
+// HasTags reports whether any resource in the inventory has a tag.
+func (inv *Inventory) HasTags() bool {
+	for _, r := range inv.Resources {
+		if len(r.Tags) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SortByTypeRegionID sorts resources by type, region and ID, the order
+// diffs, exports and the text and JSON reports list them in. Resources that
+// share all three keep their order.
+func SortByTypeRegionID(resources []Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return lessByTypeRegionID(resources[i], resources[j])
+	})
+}
+
+func lessByTypeRegionID(a, b Resource) bool {
+	if a.ResourceType != b.ResourceType {
+		return a.ResourceType < b.ResourceType
+	}
+	if a.Region != b.Region {
+		return a.Region < b.Region
+	}
+	return a.ResourceID < b.ResourceID
+}
+
 // ResourcesByType returns resources grouped by type.
 func (inv *Inventory) ResourcesByType() map[ResourceType][]Resource {
 	grouped := make(map[ResourceType][]Resource)
@@ -2,6 +2,7 @@ package awsassetinventory
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -171,6 +172,26 @@ func TestInventory_ResourcesByType(t *testing.T) {
 	}
 }
 
+func TestSortByTypeRegionID(t *testing.T) {
+	resources := []Resource{
+		{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", ResourceID: "logs"},
+		{ResourceType: "AWS::EC2::VPC", Region: "us-west-2", ResourceID: "vpc-1"},
+		{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", ResourceID: "vpc-2", Source: "first"},
+		{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", ResourceID: "vpc-1"},
+		{ResourceType: "AWS::EC2::VPC", Region: "us-east-1", ResourceID: "vpc-2", Source: "second"},
+	}
+	SortByTypeRegionID(resources)
+
+	var got []string
+	for _, r := range resources {
+		got = append(got, r.Region.String()+"/"+r.ResourceID+r.Source)
+	}
+	want := "us-east-1/vpc-1,us-east-1/vpc-2first,us-east-1/vpc-2second,us-west-2/vpc-1,us-east-1/logs"
+	if strings.Join(got, ",") != want {
+		t.Errorf("SortByTypeRegionID() = %v, want %s", got, want)
+	}
+}
+
 func TestInventory_ToJSON(t *testing.T) {
 	inv := NewInventory("test", []Region{"us-east-1"})
 	inv.AddResource(Resource{
@@ -269,3 +290,108 @@ func TestLoadFromJSON_InvalidJSON(t *testing.T) {
 		t.Error("LoadFromJSON() should return error for invalid JSON")
 	}
 }
+
+func TestInventory_CountsOnly(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1", "us-west-2"})
+	inv.CountsOnly = true
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Count: 4})
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::Instance", Region: "us-west-2", Count: 1})
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::S3::Bucket", Region: "us-west-2", Count: 2})
+
+	if got := inv.ResourceCount(); got != 7 {
+		t.Errorf("ResourceCount() = %v, want %v", got, 7)
+	}
+	if got := inv.ResourceCountByType()["AWS::EC2::Instance"]; got != 5 {
+		t.Errorf("ResourceCountByType()[EC2] = %v, want %v", got, 5)
+	}
+	if got := inv.ResourceCountByRegion()["us-west-2"]; got != 3 {
+		t.Errorf("ResourceCountByRegion()[us-west-2] = %v, want %v", got, 3)
+	}
+	if got := inv.ResourceCountByTypeAndRegion()["us-west-2"]["AWS::S3::Bucket"]; got != 2 {
+		t.Errorf("ResourceCountByTypeAndRegion()[us-west-2][S3] = %v, want %v", got, 2)
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	loaded, err := LoadFromJSON(data)
+	if err != nil {
+		t.Fatalf("LoadFromJSON() error = %v", err)
+	}
+	if !loaded.CountsOnly || loaded.ResourceCount() != 7 {
+		t.Errorf("LoadFromJSON() round trip = countsOnly %v, total %v, want true, 7", loaded.CountsOnly, loaded.ResourceCount())
+	}
+}
+
+func TestInventory_Reconcile(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1", "us-west-2"})
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Count: 2})
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::S3::Bucket", Region: "us-east-1", Count: 1})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "bucket-1", Region: "us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::IAM::Role", ResourceID: "role-1", Region: "us-east-1"})
+	// Regions without counts (e.g. failed regions) are not reconciled.
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-west-2"})
+
+	mismatches := inv.Reconcile()
+
+	want := []CountMismatch{
+		{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Expected: 2, Collected: 1},
+		{ResourceType: "AWS::IAM::Role", Region: "us-east-1", Expected: 0, Collected: 1},
+	}
+	if len(mismatches) != len(want) {
+		t.Fatalf("Reconcile() = %+v, want %+v", mismatches, want)
+	}
+	for i := range want {
+		if mismatches[i] != want[i] {
+			t.Errorf("Reconcile()[%d] = %+v, want %+v", i, mismatches[i], want[i])
+		}
+	}
+}
+
+func TestInventory_Reconcile_CountsOnly(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	inv.CountsOnly = true
+	inv.AddCount(ResourceTypeCount{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Count: 2})
+
+	if mismatches := inv.Reconcile(); len(mismatches) != 0 {
+		t.Errorf("Reconcile() on counts-only inventory = %+v, want none", mismatches)
+	}
+}
+
+func TestInventory_ResourceCountByAccount(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", AccountID: "111111111111"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", AccountID: "222222222222"})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "bucket-1", AccountID: "111111111111"})
+
+	counts := inv.ResourceCountByAccount()
+	if counts["111111111111"] != 2 || counts["222222222222"] != 1 {
+		t.Errorf("ResourceCountByAccount() = %v", counts)
+	}
+
+	byType := inv.ResourceCountByTypeAndAccount()
+	if byType["111111111111"]["AWS::S3::Bucket"] != 1 || byType["222222222222"]["AWS::EC2::Instance"] != 1 {
+		t.Errorf("ResourceCountByTypeAndAccount() = %v", byType)
+	}
+}
+
+func TestInventory_ResourceCountByAvailabilityZone(t *testing.T) {
+	inv := NewInventory("test", []Region{"us-east-1", "us-west-2"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1", AvailabilityZone: "us-east-1a"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", Region: "us-east-1", AvailabilityZone: "us-east-1a"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-3", Region: "us-west-2", AvailabilityZone: "us-west-2b"})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "bucket-1", Region: "us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::VPC", ResourceID: "vpc-1", Region: "us-east-1", AvailabilityZone: "Regional"})
+	inv.AddResource(Resource{ResourceType: "AWS::IAM::Role", ResourceID: "role-1", Region: "us-east-1", AvailabilityZone: "Not Applicable"})
+	inv.AddResource(Resource{ResourceType: "AWS::RDS::DBInstance", ResourceID: "db-1", Region: "us-east-1", AvailabilityZone: "Multiple Availability Zones"})
+
+	counts := inv.ResourceCountByAvailabilityZone()
+	if counts["us-east-1"]["us-east-1a"] != 2 || counts["us-west-2"]["us-west-2b"] != 1 {
+		t.Errorf("ResourceCountByAvailabilityZone() = %v", counts)
+	}
+	if len(counts["us-east-1"]) != 1 {
+		t.Errorf("regional resources should not be counted, got %v", counts["us-east-1"])
+	}
+}
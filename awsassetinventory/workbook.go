@@ -0,0 +1,322 @@
+package awsassetinventory
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// WorkbookGenerator generates Excel workbooks from inventory data: a
+// Summary sheet of resource counts by type, a By Region sheet of counts by
+// region and type, and a sheet per resource type listing its resources.
+// Every table has a frozen header row and an autofilter. Resource IDs link
+// to the resource in the AWS Config console, and types on the Summary sheet
+// link to their sheet.
+type WorkbookGenerator struct {
+	inventory *Inventory
+
+	// Columns are the export column names for the resource sheets, as for
+	// ParseExportColumns. When empty each sheet has the default columns for
+	// its resources.
+	Columns []string
+}
+
+// NewWorkbookGenerator creates a new WorkbookGenerator for the given inventory.
+func NewWorkbookGenerator(inv *Inventory) *WorkbookGenerator {
+	return &WorkbookGenerator{inventory: inv}
+}
+
+const (
+	summarySheet  = "Summary"
+	byRegionSheet = "By Region"
+)
+
+// Generate writes the workbook in .xlsx format to the provided writer.
+func (wg *WorkbookGenerator) Generate(w io.Writer) error {
+	if _, err := ParseExportColumns(wg.Columns, nil); err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", summarySheet); err != nil {
+		return err
+	}
+	linkStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "1265BE", Underline: "single"}})
+	if err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	if err != nil {
+		return err
+	}
+	ws := &workbookSheets{file: f, linkStyle: linkStyle, headerStyle: headerStyle}
+
+	grouped := wg.inventory.ResourcesByType()
+	sheetNames := make(map[ResourceType]string, len(grouped))
+	used := map[string]bool{strings.ToLower(summarySheet): true, strings.ToLower(byRegionSheet): true}
+	for _, rt := range sortedResourceTypes(wg.inventory.ResourceCountByType()) {
+		if len(grouped[rt]) > 0 {
+			sheetNames[rt] = workbookSheetName(rt, used)
+		}
+	}
+
+	if err := wg.writeSummary(ws, sheetNames); err != nil {
+		return err
+	}
+	if err := wg.writeByRegion(ws); err != nil {
+		return err
+	}
+	for _, rt := range sortedResourceTypes(wg.inventory.ResourceCountByType()) {
+		name, ok := sheetNames[rt]
+		if !ok {
+			continue
+		}
+		if err := wg.writeResourceSheet(ws, name, grouped[rt]); err != nil {
+			return err
+		}
+	}
+
+	f.SetActiveSheet(0)
+	return f.Write(w)
+}
+
+func (wg *WorkbookGenerator) writeSummary(ws *workbookSheets, sheetNames map[ResourceType]string) error {
+	inv := wg.inventory
+	regionStrings := make([]string, len(inv.Regions))
+	for i, r := range inv.Regions {
+		regionStrings[i] = r.String()
+	}
+	info := [][]any{
+		{"Collected", inv.CollectedAt.Format("2006-01-02 15:04:05 UTC")},
+		{"Profile", inv.Profile},
+		{"Regions", strings.Join(regionStrings, ", ")},
+	}
+	if inv.Incomplete {
+		info = append(info, []any{"Status", "Incomplete (collection failed in some regions or was interrupted)"})
+	}
+	if inv.CountsOnly {
+		info = append(info, []any{"Mode", "Counts only"})
+	}
+	info = append(info, []any{"Total Resources", inv.ResourceCount()})
+
+	for i, row := range info {
+		if err := ws.file.SetSheetRow(summarySheet, cellName(1, i+1), &row); err != nil {
+			return err
+		}
+	}
+	if err := ws.file.SetCellStyle(summarySheet, "A1", cellName(1, len(info)), ws.headerStyle); err != nil {
+		return err
+	}
+
+	counts := inv.ResourceCountByType()
+	types := sortedResourceTypes(counts)
+	rows := make([][]any, len(types))
+	for i, rt := range types {
+		rows[i] = []any{rt.String(), counts[rt]}
+	}
+
+	headerRow := len(info) + 2
+	if err := ws.writeTable(summarySheet, headerRow, []string{"Resource Type", "Count"}, rows); err != nil {
+		return err
+	}
+	for i, rt := range types {
+		name, ok := sheetNames[rt]
+		if !ok {
+			continue
+		}
+		cell := cellName(1, headerRow+1+i)
+		if err := ws.file.SetCellHyperLink(summarySheet, cell, fmt.Sprintf("'%s'!A1", name), "Location"); err != nil {
+			return err
+		}
+		if err := ws.file.SetCellStyle(summarySheet, cell, cell, ws.linkStyle); err != nil {
+			return err
+		}
+	}
+	return ws.file.SetColWidth(summarySheet, "A", "B", 40)
+}
+
+func (wg *WorkbookGenerator) writeByRegion(ws *workbookSheets) error {
+	if _, err := ws.file.NewSheet(byRegionSheet); err != nil {
+		return err
+	}
+
+	countsByRegion := wg.inventory.ResourceCountByTypeAndRegion()
+	var rows [][]any
+	for _, region := range sortedRegions(countsByRegion) {
+		typeCounts := countsByRegion[region]
+		for _, rt := range sortedResourceTypes(typeCounts) {
+			rows = append(rows, []any{region.String(), rt.String(), typeCounts[rt]})
+		}
+	}
+
+	if err := ws.writeTable(byRegionSheet, 1, []string{"Region", "Resource Type", "Count"}, rows); err != nil {
+		return err
+	}
+	if err := ws.file.SetColWidth(byRegionSheet, "A", "A", 16); err != nil {
+		return err
+	}
+	return ws.file.SetColWidth(byRegionSheet, "B", "B", 40)
+}
+
+func (wg *WorkbookGenerator) writeResourceSheet(ws *workbookSheets, sheet string, resources []Resource) error {
+	if _, err := ws.file.NewSheet(sheet); err != nil {
+		return err
+	}
+
+	sorted := make([]Resource, len(resources))
+	copy(sorted, resources)
+	sortResources(sorted)
+
+	columns := DefaultExportColumns(sorted)
+	if len(wg.Columns) > 0 {
+		var err error
+		columns, err = ParseExportColumns(wg.Columns, sorted)
+		if err != nil {
+			return err
+		}
+	}
+
+	header := make([]string, len(columns))
+	idColumn := 0
+	for i, c := range columns {
+		header[i] = c.Name
+		if c.field.field == "id" && len(c.field.path) == 0 && idColumn == 0 {
+			idColumn = i + 1
+		}
+	}
+	rows := make([][]any, len(sorted))
+	for i, r := range sorted {
+		rows[i] = make([]any, len(columns))
+		for j, c := range columns {
+			rows[i][j] = c.Value(r)
+		}
+	}
+
+	if err := ws.writeTable(sheet, 1, header, rows); err != nil {
+		return err
+	}
+
+	if idColumn > 0 {
+		for i, r := range sorted {
+			if i >= excelize.TotalSheetHyperlinks {
+				break
+			}
+			link := ConsoleURL(r)
+			if link == "" {
+				continue
+			}
+			cell := cellName(idColumn, i+2)
+			tooltip := "Open in the AWS Config console"
+			if err := ws.file.SetCellHyperLink(sheet, cell, link, "External", excelize.HyperlinkOpts{Tooltip: &tooltip}); err != nil {
+				return err
+			}
+			if err := ws.file.SetCellStyle(sheet, cell, cell, ws.linkStyle); err != nil {
+				return err
+			}
+		}
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(len(columns))
+	if err != nil {
+		return err
+	}
+	return ws.file.SetColWidth(sheet, "A", lastColumn, 24)
+}
+
+// workbookSheets holds the file and shared styles while a workbook is
+// written.
+type workbookSheets struct {
+	file        *excelize.File
+	linkStyle   int
+	headerStyle int
+}
+
+// writeTable writes a header row at headerRow followed by rows, freezes
+// the rows down to the header and adds an autofilter over the table.
+func (ws *workbookSheets) writeTable(sheet string, headerRow int, header []string, rows [][]any) error {
+	if err := ws.file.SetSheetRow(sheet, cellName(1, headerRow), &header); err != nil {
+		return err
+	}
+	lastHeader := cellName(len(header), headerRow)
+	if err := ws.file.SetCellStyle(sheet, cellName(1, headerRow), lastHeader, ws.headerStyle); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if err := ws.file.SetSheetRow(sheet, cellName(1, headerRow+1+i), &row); err != nil {
+			return err
+		}
+	}
+
+	if err := ws.file.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      headerRow,
+		TopLeftCell: cellName(1, headerRow+1),
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	return ws.file.AutoFilter(sheet, fmt.Sprintf("%s:%s", cellName(1, headerRow), cellName(len(header), headerRow+len(rows))), nil)
+}
+
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
+
+// workbookSheetName returns a unique sheet name for resourceType, such as
+// "EC2 Instance" for AWS::EC2::Instance, within Excel's 31 character limit.
+// used holds the lower-cased names already taken and is updated.
+func workbookSheetName(resourceType ResourceType, used map[string]bool) string {
+	name := strings.TrimPrefix(resourceType.String(), "AWS::")
+	name = strings.ReplaceAll(name, "::", " ")
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\'`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	base := []rune(name)
+	if len(base) > excelize.MaxSheetNameLength {
+		base = base[:excelize.MaxSheetNameLength]
+	}
+	candidate := string(base)
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		trimmed := base
+		if len(trimmed)+len(suffix) > excelize.MaxSheetNameLength {
+			trimmed = trimmed[:excelize.MaxSheetNameLength-len(suffix)]
+		}
+		candidate = string(trimmed) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// ConsoleURL returns the address of r in the AWS Config console, or "" when
+// r has no region or ID.
+func ConsoleURL(r Resource) string {
+	if r.Region == "" || r.ResourceID == "" {
+		return ""
+	}
+	region := r.Region.String()
+	host := region + ".console.aws.amazon.com"
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		host = region + ".console.amazonaws.cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		host = "console.amazonaws-us-gov.com"
+	}
+	query := url.Values{}
+	query.Set("resourceId", r.ResourceID)
+	query.Set("resourceType", r.ResourceType.String())
+	return fmt.Sprintf("https://%s/config/home?region=%s#/resources/details?%s", host, url.QueryEscape(region), query.Encode())
+}
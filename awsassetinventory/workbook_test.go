@@ -0,0 +1,149 @@
+package awsassetinventory
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func workbookTestInventory() *Inventory {
+	inv := NewInventory("prod", []Region{"us-east-1", "us-west-2"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", ResourceName: "web", Region: "us-east-1",
+		Tags: map[string]string{"env": "prod"}})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-2", ResourceName: "worker", Region: "us-west-2"})
+	inv.AddResource(Resource{ResourceType: "AWS::EC2::SecurityGroup", ResourceID: "sg-1", Region: "us-east-1"})
+	inv.AddResource(Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+	return inv
+}
+
+func generateWorkbook(t *testing.T, wg *WorkbookGenerator) *excelize.File {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := wg.Generate(&buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("output is not a workbook: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestWorkbookGenerator_Generate(t *testing.T) {
+	inv := workbookTestInventory()
+	f := generateWorkbook(t, NewWorkbookGenerator(inv))
+
+	want := []string{"Summary", "By Region", "EC2 Instance", "EC2 SecurityGroup", "S3 Bucket"}
+	if got := f.GetSheetList(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("sheets = %v, want %v", got, want)
+	}
+
+	summary, err := f.GetRows("Summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for i, row := range summary {
+		if len(row) == 2 && row[0] == "AWS::EC2::Instance" {
+			found = true
+			if row[1] != "2" {
+				t.Errorf("summary count = %s, want 2", row[1])
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			ok, link, err := f.GetCellHyperLink("Summary", cell)
+			if err != nil || !ok || link != "'EC2 Instance'!A1" {
+				t.Errorf("summary type link = %v %q %v, want a link to the sheet", ok, link, err)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("summary should list AWS::EC2::Instance, got %v", summary)
+	}
+
+	byRegion, err := f.GetRows("By Region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(byRegion[0], []string{"Region", "Resource Type", "Count"}) || len(byRegion) != 5 {
+		t.Errorf("By Region rows = %v", byRegion)
+	}
+	panes, err := f.GetPanes("By Region")
+	if err != nil || !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("By Region panes = %+v, %v, want the header row frozen", panes, err)
+	}
+
+	rows, err := f.GetRows("EC2 Instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows[0], []string{"type", "id", "name", "region", "availabilityZone", "account", "arn", "tags.env"}) || len(rows) != 3 {
+		t.Errorf("EC2 Instance rows = %v", rows)
+	}
+	ok, link, err := f.GetCellHyperLink("EC2 Instance", "B2")
+	if err != nil || !ok || !strings.HasPrefix(link, "https://us-east-1.console.aws.amazon.com/config/home?region=us-east-1#/resources/details?") {
+		t.Errorf("resource ID link = %v %q %v", ok, link, err)
+	}
+}
+
+func TestWorkbookGenerator_Columns(t *testing.T) {
+	wg := NewWorkbookGenerator(workbookTestInventory())
+	wg.Columns = []string{"id", "region"}
+	f := generateWorkbook(t, wg)
+
+	rows, err := f.GetRows("S3 Bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows[0], []string{"id", "region"}) {
+		t.Errorf("header = %v, want the chosen columns", rows[0])
+	}
+
+	wg.Columns = []string{"bogus"}
+	if err := wg.Generate(&bytes.Buffer{}); err == nil {
+		t.Error("Generate() should reject an unknown column")
+	}
+}
+
+func TestWorkbookSheetName(t *testing.T) {
+	used := map[string]bool{"summary": true}
+	tests := []struct {
+		rt   ResourceType
+		want string
+	}{
+		{"AWS::EC2::Instance", "EC2 Instance"},
+		{"AWS::ElasticLoadBalancingV2::LoadBalancer", "ElasticLoadBalancingV2 LoadBala"},
+		{"AWS::ElasticLoadBalancingV2::LoadBalancerX", "ElasticLoadBalancingV2 Load (2)"},
+		{"Custom::Thing/With?Chars", "Custom Thing_With_Chars"},
+	}
+	for _, tt := range tests {
+		if got := workbookSheetName(tt.rt, used); got != tt.want {
+			t.Errorf("workbookSheetName(%s) = %q, want %q", tt.rt, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		r    Resource
+		want string
+	}{
+		{
+			Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "my bucket", Region: "eu-west-1"},
+			"https://eu-west-1.console.aws.amazon.com/config/home?region=eu-west-1#/resources/details?resourceId=my+bucket&resourceType=AWS%3A%3AS3%3A%3ABucket",
+		},
+		{
+			Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "b", Region: "cn-north-1"},
+			"https://cn-north-1.console.amazonaws.cn/config/home?region=cn-north-1#/resources/details?resourceId=b&resourceType=AWS%3A%3AS3%3A%3ABucket",
+		},
+		{Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "b"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ConsoleURL(tt.r); got != tt.want {
+			t.Errorf("ConsoleURL() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
@@ -19,6 +23,12 @@ var (
 	collectOutput      string
 	collectVerbose     bool
 	collectConcurrency int
+	collectCountsOnly  bool
+	collectAggregator  string
+	collectAggRegion   string
+	collectTimeout     time.Duration
+	collectRegionTO    time.Duration
+	collectStore       string
 )
 
 var collectCmd = &cobra.Command{
@@ -35,13 +45,17 @@ func init() {
 	collectCmd.Flags().StringVarP(&collectOutput, "output", "o", "", "Output file path (default: stdout)")
 	collectCmd.Flags().BoolVarP(&collectVerbose, "verbose", "v", false, "Show detailed progress during collection")
 	collectCmd.Flags().IntVar(&collectConcurrency, "concurrency", 0, "Max concurrent region collections (default 5)")
+	collectCmd.Flags().BoolVar(&collectCountsOnly, "counts-only", false, "Collect only per-type resource counts (fast census)")
+	collectCmd.Flags().StringVar(&collectAggregator, "aggregator", "", "AWS Config aggregator name to count through (requires --counts-only)")
+	collectCmd.Flags().StringVar(&collectAggRegion, "aggregator-region", "", "Region the aggregator lives in (default: first of --regions)")
+	collectCmd.Flags().DurationVar(&collectTimeout, "timeout", 0, "Overall deadline for the collection, e.g. 45m (default: none)")
+	collectCmd.Flags().DurationVar(&collectRegionTO, "region-timeout", 0, "Deadline for each region, e.g. 10m (default: none)")
+	collectCmd.Flags().StringVar(&collectStore, "store", "", "Also save the inventory as a snapshot in this SQLite store")
 
 	_ = collectCmd.MarkFlagRequired("regions")
 }
 
 func runCollect(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-
 	regionList := parseRegions(collectRegions)
 	if len(regionList) == 0 {
 		return fmt.Errorf("at least one region must be specified")
@@ -53,71 +67,217 @@ func runCollect(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if collectProfile != "" {
-		fmt.Fprintf(os.Stderr, "Collecting resources from %d region(s) using profile '%s'...\n", len(regionList), collectProfile)
-	} else {
-		fmt.Fprintf(os.Stderr, "Collecting resources from %d region(s) using default credentials...\n", len(regionList))
+	if collectAggregator != "" && !collectCountsOnly {
+		return fmt.Errorf("--aggregator requires --counts-only")
 	}
 
-	clientFactory := func(region awsassetinventory.Region) awsassetinventory.ConfigClient {
-		opts := []func(*config.LoadOptions) error{
-			config.WithRegion(region.String()),
+	if collectTimeout < 0 || collectRegionTO < 0 {
+		return fmt.Errorf("--timeout and --region-timeout must not be negative")
+	}
+
+	aggregatorRegion := regionList[0]
+	if collectAggRegion != "" {
+		aggregatorRegion = awsassetinventory.Region(collectAggRegion)
+		if !aggregatorRegion.IsValid() {
+			return fmt.Errorf("invalid aggregator region: %s", aggregatorRegion)
 		}
-		if collectProfile != "" {
-			opts = append(opts, config.WithSharedConfigProfile(collectProfile))
+	}
+
+	// Cancel in-flight calls on SIGINT/SIGTERM so the partial inventory can
+	// still be written. A second signal terminates immediately.
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		stop()
+	}()
+
+	ctx := sigCtx
+	if collectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, collectTimeout)
+		defer cancel()
+	}
+
+	log := logger
+	if collectVerbose {
+		// --verbose is shorthand for --log-level debug.
+		l, err := newLogger(os.Stderr, "debug", logFormat)
+		if err != nil {
+			return err
 		}
-		cfg, err := config.LoadDefaultConfig(ctx, opts...)
+		log = l
+	}
+
+	shutdownTel, err := setupTelemetry(ctx, otelEndpoint)
+	if err != nil {
+		return err
+	}
+	defer shutdownTelemetry(shutdownTel)
+
+	// On a terminal the live display replaces the collector's per-region log
+	// records, which would otherwise break the in-place redraw. Debug logging
+	// and JSON logs keep the structured records instead. Everything else
+	// logged to stderr goes through the display so it is printed above the
+	// status lines, and so do the collector's warnings and errors, such as
+	// throttling and failed regions.
+	var progress *liveProgress
+	var collectorLog *slog.Logger
+	if isTerminal(os.Stderr) && !log.Enabled(ctx, slog.LevelDebug) && strings.EqualFold(logFormat, "text") {
+		progress = newLiveProgress(os.Stderr, regionList)
+		l, err := newLogger(progress, logLevel, logFormat)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to load config for region %s: %v\n", region, err)
-			return nil
+			return err
 		}
-		return configservice.NewFromConfig(cfg)
+		log = l
+
+		level := "warn"
+		if !log.Enabled(ctx, slog.LevelWarn) {
+			level = logLevel
+		}
+		collectorLog, err = newLogger(progress, level, logFormat)
+		if err != nil {
+			return err
+		}
+	}
+
+	credentials := collectProfile
+	if credentials == "" {
+		credentials = "default credentials"
 	}
+	log.Info("collecting resources", "regions", len(regionList), "profile", credentials)
 
-	collector := awsassetinventory.NewCollector(collectProfile, clientFactory)
+	collector := awsassetinventory.NewCollector(collectProfile, newClientFactory(ctx, collectProfile, log))
 	if collectConcurrency > 0 {
 		collector.MaxConcurrency = collectConcurrency
 	}
-	if collectVerbose {
-		collector.Logger = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+	collector.RegionTimeout = collectRegionTO
+	if progress != nil {
+		collector.Observer = progress
+		collector.Logger = collectorLog
+	} else {
+		collector.Logger = log
 	}
 
-	inventory, err := collector.Collect(ctx, regionList)
+	var inventory *awsassetinventory.Inventory
+	switch {
+	case collectAggregator != "":
+		inventory, err = collector.CollectAggregateCounts(ctx, collectAggregator, aggregatorRegion, regionList)
+	case collectCountsOnly:
+		inventory, err = collector.CollectCounts(ctx, regionList)
+	default:
+		inventory, err = collector.Collect(ctx, regionList)
+	}
+	if progress != nil {
+		progress.Finish()
+	}
 	if err != nil {
 		var collectErrs awsassetinventory.CollectErrors
 		if errors.As(err, &collectErrs) {
 			failedRegions := collectErrs.Regions()
-			fmt.Fprintf(os.Stderr, "Warning: %d region(s) failed: %s\n",
-				len(failedRegions), strings.Join(regionStrings(failedRegions), ", "))
+			log.Warn("some regions failed",
+				"failed", len(failedRegions), "regions", strings.Join(regionStrings(failedRegions), ","))
 			for _, re := range collectErrs.Errors {
-				fmt.Fprintf(os.Stderr, "  [%s] %v\n", re.Region, re.Err)
+				log.Warn("region failed", "region", re.Region.String(), "error", re.Err)
 			}
 		} else {
-			fmt.Fprintf(os.Stderr, "Warning: collection completed with errors: %v\n", err)
+			log.Warn("collection completed with errors", "error", err)
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "Collected %d resources\n", inventory.ResourceCount())
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// The collector only marks the inventory incomplete when a region
+		// failed, which a cancellation after the last region does not cause.
+		inventory.Incomplete = true
+		log.Warn("collection stopped early; writing partial inventory marked incomplete", "error", ctxErr)
+	}
+
+	if inventory.CountsOnly {
+		log.Info("collection complete", "resources", inventory.ResourceCount(),
+			"resource_types", len(inventory.ResourceCountByType()), "counts_only", true)
+	} else {
+		log.Info("collection complete", "resources", inventory.ResourceCount())
+	}
+
+	if len(inventory.Failures) > 0 {
+		log.Warn("configuration could not be retrieved for some resources; they are listed in the inventory's failures",
+			"resources", len(inventory.Failures))
+	}
+
+	for _, m := range inventory.Mismatches {
+		log.Warn("collected resources differ from AWS Config count",
+			"region", m.Region.String(), "resource_type", m.ResourceType.String(),
+			"expected", m.Expected, "collected", m.Collected)
+	}
 
+	return writeCollected(inventory, collectOutput, collectStore, log)
+}
+
+// writeCollected writes inventory as JSON to output, or stdout, and then
+// saves it to the SQLite store at store, if set. The JSON is written first
+// so that a store that cannot be opened or written never costs the
+// collection; the store's error is returned once the output exists.
+func writeCollected(inventory *awsassetinventory.Inventory, output, store string, log *slog.Logger) error {
 	data, err := inventory.ToJSON()
 	if err != nil {
 		return fmt.Errorf("failed to serialize JSON: %w", err)
 	}
 
-	if collectOutput == "" || collectOutput == "-" {
+	if output == "" || output == "-" {
 		fmt.Println(string(data))
 	} else {
-		if err := os.WriteFile(collectOutput, data, 0644); err != nil {
+		if err := os.WriteFile(output, data, 0644); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
-		fmt.Fprintf(os.Stderr, "Inventory written to: %s\n", collectOutput)
+		log.Info("inventory written", "path", output)
+	}
+
+	if store != "" {
+		if err := saveSnapshot(store, inventory, log); err != nil {
+			return fmt.Errorf("inventory was written but not stored: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveSnapshot stores inventory as a new snapshot in the SQLite store at
+// path, creating the store if needed.
+func saveSnapshot(path string, inventory *awsassetinventory.Inventory, log *slog.Logger) error {
+	store, err := awsassetinventory.OpenStore(path)
+	if err != nil {
+		return err
 	}
+	defer store.Close()
 
+	// Use a fresh context: the collection's may already be cancelled, and a
+	// partial inventory is still worth keeping.
+	id, err := store.Save(context.Background(), inventory)
+	if err != nil {
+		return err
+	}
+	log.Info("snapshot stored", "store", path, "snapshot", id)
 	return nil
 }
 
+// newClientFactory returns a factory that builds AWS Config clients for
+// profile, or for the default credential chain when profile is empty.
+func newClientFactory(ctx context.Context, profile string, log *slog.Logger) awsassetinventory.ConfigClientFactory {
+	return func(region awsassetinventory.Region) awsassetinventory.ConfigClient {
+		opts := []func(*config.LoadOptions) error{
+			config.WithRegion(region.String()),
+		}
+		if profile != "" {
+			opts = append(opts, config.WithSharedConfigProfile(profile))
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			log.Warn("failed to load AWS config", "region", region.String(), "error", err)
+			return nil
+		}
+		return configservice.NewFromConfig(cfg)
+	}
+}
+
 func parseRegions(input string) []awsassetinventory.Region {
 	parts := strings.Split(input, ",")
 	regions := make([]awsassetinventory.Region, 0, len(parts))
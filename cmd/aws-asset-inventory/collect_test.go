@@ -1,7 +1,9 @@
 package main
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
 )
@@ -99,3 +101,62 @@ func TestCollectValidatesRegions(t *testing.T) {
 		t.Error("runCollect should return error for invalid region")
 	}
 }
+
+func TestCollectAggregatorRequiresCountsOnly(t *testing.T) {
+	// Save original values
+	origRegions := collectRegions
+	origAggregator := collectAggregator
+	origCountsOnly := collectCountsOnly
+	t.Cleanup(func() {
+		collectRegions = origRegions
+		collectAggregator = origAggregator
+		collectCountsOnly = origCountsOnly
+	})
+
+	collectRegions = "us-east-1"
+	collectAggregator = "org-aggregator"
+	collectCountsOnly = false
+
+	err := runCollect(nil, nil)
+	if err == nil {
+		t.Error("runCollect should return error when --aggregator is used without --counts-only")
+	}
+}
+
+func TestCollectRejectsNegativeTimeout(t *testing.T) {
+	// Save original values
+	origRegions := collectRegions
+	origTimeout := collectTimeout
+	t.Cleanup(func() {
+		collectRegions = origRegions
+		collectTimeout = origTimeout
+	})
+
+	collectRegions = "us-east-1"
+	collectTimeout = -time.Second
+
+	err := runCollect(nil, nil)
+	if err == nil {
+		t.Error("runCollect should return error for a negative timeout")
+	}
+}
+
+func TestWriteCollectedBeforeStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+	output := filepath.Join(tmpDir, "inventory.json")
+	store := filepath.Join(tmpDir, "missing", "dir", "inventory.db")
+
+	if err := writeCollected(inv, output, store, logger); err == nil {
+		t.Error("writeCollected should return the store's error")
+	}
+
+	got, err := awsassetinventory.LoadFromFile(output)
+	if err != nil {
+		t.Fatalf("inventory should be written even when the store fails: %v", err)
+	}
+	if len(got.Resources) != 1 {
+		t.Errorf("written resources = %d, want 1", len(got.Resources))
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonProfile     string
+	daemonRegions     string
+	daemonSchedule    string
+	daemonSnapshotDir string
+	daemonRetain      int
+	daemonConcurrency int
+	daemonTimeout     time.Duration
+	daemonRegionTO    time.Duration
+	daemonRunNow      bool
+	daemonListen      string
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Collect resources on a schedule",
+	Long: `Run as a long-lived process that collects resources on a cron schedule.
+Each inventory is kept in memory and written to the snapshot directory, and
+old snapshots are pruned. A run is skipped if the previous one is still going.`,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().StringVarP(&daemonProfile, "profile", "p", "", "AWS profile name (uses default credential chain if omitted)")
+	daemonCmd.Flags().StringVarP(&daemonRegions, "regions", "r", "", "Comma-separated list of AWS regions (required)")
+	daemonCmd.Flags().StringVar(&daemonSchedule, "schedule", "", `Cron schedule in UTC, e.g. "0 */6 * * *" or "@every 6h" (required)`)
+	daemonCmd.Flags().StringVar(&daemonSnapshotDir, "snapshot-dir", "", "Directory to write inventory snapshots to (required)")
+	daemonCmd.Flags().IntVar(&daemonRetain, "retain", 30, "Number of snapshots to keep (0 keeps all)")
+	daemonCmd.Flags().IntVar(&daemonConcurrency, "concurrency", 0, "Max concurrent region collections (default 5)")
+	daemonCmd.Flags().DurationVar(&daemonTimeout, "timeout", 0, "Deadline for each collection run, e.g. 45m (default: none)")
+	daemonCmd.Flags().DurationVar(&daemonRegionTO, "region-timeout", 0, "Deadline for each region, e.g. 10m (default: none)")
+	daemonCmd.Flags().BoolVar(&daemonRunNow, "run-now", false, "Collect once at startup instead of waiting for the first scheduled run")
+	daemonCmd.Flags().StringVar(&daemonListen, "listen", "", "Also serve the HTTP API and /metrics for the latest inventory on this address")
+
+	_ = daemonCmd.MarkFlagRequired("regions")
+	_ = daemonCmd.MarkFlagRequired("schedule")
+	_ = daemonCmd.MarkFlagRequired("snapshot-dir")
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	regionList := parseRegions(daemonRegions)
+	if len(regionList) == 0 {
+		return fmt.Errorf("at least one region must be specified")
+	}
+	for _, r := range regionList {
+		if !r.IsValid() {
+			return fmt.Errorf("invalid region: %s", r)
+		}
+	}
+
+	schedule, err := cron.ParseStandard(daemonSchedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", daemonSchedule, err)
+	}
+
+	if daemonRetain < 0 {
+		return fmt.Errorf("--retain must not be negative")
+	}
+	if daemonTimeout < 0 || daemonRegionTO < 0 {
+		return fmt.Errorf("--timeout and --region-timeout must not be negative")
+	}
+
+	// Stop scheduling on SIGINT/SIGTERM and cancel a run in progress; the
+	// partial inventory is still written as an incomplete snapshot.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTel, err := setupTelemetry(ctx, otelEndpoint)
+	if err != nil {
+		return err
+	}
+	defer shutdownTelemetry(shutdownTel)
+
+	collector := awsassetinventory.NewCollector(daemonProfile, newClientFactory(ctx, daemonProfile, logger))
+	if daemonConcurrency > 0 {
+		collector.MaxConcurrency = daemonConcurrency
+	}
+	collector.RegionTimeout = daemonRegionTO
+	collector.Logger = logger
+
+	daemon := awsassetinventory.NewDaemon(collector, regionList,
+		awsassetinventory.NewSnapshotDir(daemonSnapshotDir, daemonRetain))
+	daemon.Logger = logger
+	daemon.RunTimeout = daemonTimeout
+
+	var ln net.Listener
+	if daemonListen != "" {
+		if ln, err = net.Listen("tcp", daemonListen); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", daemonListen, err)
+		}
+	}
+
+	// RunOnce logs each run's outcome, including skipped runs, so a failed
+	// run only needs to wait for the next scheduled one.
+	run := func() { _, _ = daemon.RunOnce(ctx) }
+
+	scheduler := cron.New(cron.WithLocation(time.UTC))
+	scheduler.Schedule(schedule, cron.FuncJob(run))
+	scheduler.Start()
+
+	logger.Info("daemon started", "schedule", daemonSchedule,
+		"next_run", schedule.Next(time.Now().UTC()), "snapshot_dir", daemonSnapshotDir, "retain", daemonRetain)
+
+	var background sync.WaitGroup
+	if ln != nil {
+		srv := awsassetinventory.NewServer(daemon.Source())
+		srv.Outcome = daemon.Outcome
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			if err := serveHTTP(ctx, ln, srv); err != nil {
+				logger.Error("HTTP server failed", "error", err)
+			}
+		}()
+	}
+
+	if daemonRunNow {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run()
+		}()
+	}
+
+	<-ctx.Done()
+	logger.Info("shutting down; waiting for the current collection to stop")
+	<-scheduler.Stop().Done()
+	background.Wait()
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDaemonValidatesSchedule(t *testing.T) {
+	// Save original values
+	origRegions := daemonRegions
+	origSchedule := daemonSchedule
+	t.Cleanup(func() {
+		daemonRegions = origRegions
+		daemonSchedule = origSchedule
+	})
+
+	daemonRegions = "us-east-1"
+	daemonSchedule = "every tuesday"
+
+	err := runDaemon(nil, nil)
+	if err == nil {
+		t.Error("runDaemon should return error for an invalid schedule")
+	}
+}
+
+func TestDaemonRejectsNegativeRetain(t *testing.T) {
+	// Save original values
+	origRegions := daemonRegions
+	origSchedule := daemonSchedule
+	origRetain := daemonRetain
+	t.Cleanup(func() {
+		daemonRegions = origRegions
+		daemonSchedule = origSchedule
+		daemonRetain = origRetain
+	})
+
+	daemonRegions = "us-east-1"
+	daemonSchedule = "@every 6h"
+	daemonRetain = -1
+
+	err := runDaemon(nil, nil)
+	if err == nil {
+		t.Error("runDaemon should return error for a negative --retain")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFrom   string
+	diffTo     string
+	diffFormat string
+	diffOutput string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two inventories",
+	Long: `Compare two inventory JSON files and report the resources that were added,
+removed or whose configuration changed, with counts per type and region.
+Resources are matched by ARN, or by type, region and ID when either copy
+has no ARN.`,
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "Earlier inventory JSON file (required)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "Later inventory JSON file (required)")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "markdown", "Output format: markdown or json")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Output file path (default: stdout)")
+
+	_ = diffCmd.MarkFlagRequired("from")
+	_ = diffCmd.MarkFlagRequired("to")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffFormat != "markdown" && diffFormat != "json" {
+		return fmt.Errorf("invalid format %q: must be markdown or json", diffFormat)
+	}
+
+	from, err := awsassetinventory.LoadFromFile(diffFrom)
+	if err != nil {
+		return fmt.Errorf("failed to load --from inventory: %w", err)
+	}
+	to, err := awsassetinventory.LoadFromFile(diffTo)
+	if err != nil {
+		return fmt.Errorf("failed to load --to inventory: %w", err)
+	}
+
+	diff, err := awsassetinventory.DiffInventories(from, to)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(diffOutput, func(w io.Writer) error {
+		if diffFormat == "json" {
+			data, err := diff.ToJSON()
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON: %w", err)
+			}
+			_, err = fmt.Fprintln(w, string(data))
+			return err
+		}
+		return awsassetinventory.NewDiffReportGenerator(diff).Generate(w)
+	})
+}
+
+// writeOutput calls write with stdout, or with the file at path unless path
+// is empty or "-".
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "" || path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	logger.Info("output written", "path", path)
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func writeInventoryFile(t *testing.T, dir, name string, inv *awsassetinventory.Inventory) string {
+	t.Helper()
+	data, err := inv.ToJSON()
+	if err != nil {
+		t.Fatalf("failed to serialize inventory: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to create inventory file: %v", err)
+	}
+	return path
+}
+
+func TestDiffJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	from := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	to := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	to.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+
+	// Save original values
+	origFrom, origTo, origFormat, origOutput := diffFrom, diffTo, diffFormat, diffOutput
+	t.Cleanup(func() {
+		diffFrom, diffTo, diffFormat, diffOutput = origFrom, origTo, origFormat, origOutput
+	})
+
+	diffFrom = writeInventoryFile(t, tmpDir, "from.json", from)
+	diffTo = writeInventoryFile(t, tmpDir, "to.json", to)
+	diffFormat = "json"
+	diffOutput = filepath.Join(tmpDir, "diff.json")
+
+	if err := runDiff(nil, nil); err != nil {
+		t.Fatalf("runDiff failed: %v", err)
+	}
+
+	data, err := os.ReadFile(diffOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got awsassetinventory.InventoryDiff
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("diff output is not JSON: %v", err)
+	}
+	if len(got.Added) != 1 {
+		t.Errorf("diff added = %d, want 1", len(got.Added))
+	}
+}
+
+func TestDiffRejectsUnknownFormat(t *testing.T) {
+	// Save original values
+	origFormat := diffFormat
+	t.Cleanup(func() { diffFormat = origFormat })
+
+	diffFormat = "html"
+	if err := runDiff(nil, nil); err == nil {
+		t.Error("runDiff should return error for an unknown format")
+	}
+}
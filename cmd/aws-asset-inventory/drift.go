@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	driftFrom           string
+	driftTo             string
+	driftRules          string
+	driftNoDefaultRules bool
+	driftFormat         string
+	driftOutput         string
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Show field-level configuration changes between two inventories",
+	Long: `Compare the configuration of every resource present in both inventories and
+list each changed field as a JSON pointer with its old and new value.
+
+Noisy fields such as capture timestamps are ignored by default. Add your own
+ignore rules per resource type with --rules, a JSON file of the form:
+
+  {"ignore": {"*": ["/some/field"], "AWS::EC2::Instance": ["/launchTime"]}}
+
+A "*" token in a path matches any key or array index.`,
+	RunE: runDrift,
+}
+
+func init() {
+	driftCmd.Flags().StringVar(&driftFrom, "from", "", "Earlier inventory JSON file (required)")
+	driftCmd.Flags().StringVar(&driftTo, "to", "", "Later inventory JSON file (required)")
+	driftCmd.Flags().StringVar(&driftRules, "rules", "", "JSON file of fields to ignore per resource type")
+	driftCmd.Flags().BoolVar(&driftNoDefaultRules, "no-default-rules", false, "Do not ignore the built-in noisy fields")
+	driftCmd.Flags().StringVarP(&driftFormat, "format", "f", "markdown", "Output format: markdown or json")
+	driftCmd.Flags().StringVarP(&driftOutput, "output", "o", "", "Output file path (default: stdout)")
+
+	_ = driftCmd.MarkFlagRequired("from")
+	_ = driftCmd.MarkFlagRequired("to")
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	if driftFormat != "markdown" && driftFormat != "json" {
+		return fmt.Errorf("invalid format %q: must be markdown or json", driftFormat)
+	}
+
+	var rules *awsassetinventory.DriftRules
+	if !driftNoDefaultRules {
+		rules = awsassetinventory.DefaultDriftRules()
+	}
+	if driftRules != "" {
+		custom, err := awsassetinventory.LoadDriftRules(driftRules)
+		if err != nil {
+			return fmt.Errorf("failed to load drift rules: %w", err)
+		}
+		rules = rules.Merge(custom)
+	}
+
+	from, err := awsassetinventory.LoadFromFile(driftFrom)
+	if err != nil {
+		return fmt.Errorf("failed to load --from inventory: %w", err)
+	}
+	to, err := awsassetinventory.LoadFromFile(driftTo)
+	if err != nil {
+		return fmt.Errorf("failed to load --to inventory: %w", err)
+	}
+
+	diff, err := awsassetinventory.DiffInventories(from, to)
+	if err != nil {
+		return err
+	}
+	drift, err := diff.Drift(rules)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(driftOutput, func(w io.Writer) error {
+		if driftFormat == "json" {
+			data, err := drift.ToJSON()
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON: %w", err)
+			}
+			_, err = fmt.Fprintln(w, string(data))
+			return err
+		}
+		return awsassetinventory.NewDriftReportGenerator(drift).Generate(w)
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestDriftWithRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	from := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	from.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		Configuration: json.RawMessage(`{"versioning":"Off","owner":"a"}`)})
+	to := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	to.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		Configuration: json.RawMessage(`{"versioning":"Enabled","owner":"b"}`)})
+
+	rulesFile := filepath.Join(tmpDir, "rules.json")
+	if err := os.WriteFile(rulesFile, []byte(`{"ignore":{"AWS::S3::Bucket":["/owner"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save original values
+	origFrom, origTo, origRules, origFormat, origOutput := driftFrom, driftTo, driftRules, driftFormat, driftOutput
+	t.Cleanup(func() {
+		driftFrom, driftTo, driftRules, driftFormat, driftOutput = origFrom, origTo, origRules, origFormat, origOutput
+	})
+
+	driftFrom = writeInventoryFile(t, tmpDir, "from.json", from)
+	driftTo = writeInventoryFile(t, tmpDir, "to.json", to)
+	driftRules = rulesFile
+	driftFormat = "json"
+	driftOutput = filepath.Join(tmpDir, "drift.json")
+
+	if err := runDrift(nil, nil); err != nil {
+		t.Fatalf("runDrift failed: %v", err)
+	}
+
+	data, err := os.ReadFile(driftOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got awsassetinventory.DriftReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("drift output is not JSON: %v", err)
+	}
+	if len(got.Resources) != 1 || len(got.Resources[0].Changes) != 1 || got.Resources[0].Changes[0].Path != "/versioning" {
+		t.Errorf("drift = %+v, want only /versioning", got.Resources)
+	}
+}
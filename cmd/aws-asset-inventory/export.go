@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportInput       string
+	exportSnapshot    string
+	exportStore       string
+	exportFormat      string
+	exportOutput      string
+	exportColumns     []string
+	exportSplitByType bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export inventory resources as a spreadsheet",
+	Long: `Export the resources of an inventory as a table with a row per resource, for
+use in spreadsheets.
+
+The csv format writes a single table. The xlsx format writes an Excel workbook
+with a Summary sheet of counts by resource type, a By Region sheet and one
+sheet per resource type; resource IDs link to the AWS Config console.
+
+By default the columns are the resource fields (type, id, name, region,
+availabilityZone, account and arn) followed by a column per tag key. Choose
+columns with --columns, naming fields as in filter expressions: a resource
+field, tags.<key>, tags.* for every tag key, or config.<path> for a
+configuration value, such as config.instanceType or
+config.securityGroups[0].groupId. Configuration objects and lists are
+written as JSON. CSV values that a spreadsheet would run as formulas are
+prefixed with ' so they are shown as text.`,
+	Example: `  aws-asset-inventory export --input inventory.json --output inventory.csv
+  aws-asset-inventory export --input inventory.json --columns type,id,region,tags.owner,config.instanceType
+  aws-asset-inventory export --input inventory.json --split-by-type --output exports/
+  aws-asset-inventory export --input inventory.json --format xlsx --output inventory.xlsx`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportInput, "input", "i", "", "Input JSON inventory file (- for stdin)")
+	exportCmd.Flags().StringVar(&exportSnapshot, "snapshot", "", `Snapshot ID to export, or "latest" (requires --store)`)
+	exportCmd.Flags().StringVar(&exportStore, "store", "", "SQLite store to read --snapshot from")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "Output format: csv or xlsx")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file path, or directory with --split-by-type (default: stdout)")
+	exportCmd.Flags().StringSliceVar(&exportColumns, "columns", nil, "Columns to export (default: resource fields and all tags)")
+	exportCmd.Flags().BoolVar(&exportSplitByType, "split-by-type", false, "Write one file per resource type into the --output directory")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "csv" && exportFormat != "xlsx" {
+		return fmt.Errorf("invalid format %q: must be csv or xlsx", exportFormat)
+	}
+	if exportSplitByType && exportFormat == "xlsx" {
+		return fmt.Errorf("--split-by-type applies to csv only: xlsx already has a sheet per resource type")
+	}
+	if exportSplitByType && (exportOutput == "" || exportOutput == "-") {
+		return fmt.Errorf("--split-by-type requires --output to name a directory")
+	}
+	// Check the column names before loading anything.
+	if _, err := awsassetinventory.ParseExportColumns(exportColumns, nil); err != nil {
+		return err
+	}
+
+	inventory, err := loadInventory(exportInput, exportSnapshot, exportStore)
+	if err != nil {
+		return err
+	}
+	if exportFormat == "xlsx" {
+		wg := awsassetinventory.NewWorkbookGenerator(inventory)
+		wg.Columns = exportColumns
+		return writeOutput(exportOutput, wg.Generate)
+	}
+	if inventory.CountsOnly {
+		return fmt.Errorf("cannot export a counts-only inventory as csv: it has no resources")
+	}
+
+	resources := make([]awsassetinventory.Resource, len(inventory.Resources))
+	copy(resources, inventory.Resources)
+	awsassetinventory.SortByTypeRegionID(resources)
+
+	if !exportSplitByType {
+		return writeOutput(exportOutput, func(w io.Writer) error {
+			return writeExport(w, resources)
+		})
+	}
+
+	if err := os.MkdirAll(exportOutput, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for start := 0; start < len(resources); {
+		rt := resources[start].ResourceType
+		end := start
+		for end < len(resources) && resources[end].ResourceType == rt {
+			end++
+		}
+		path := filepath.Join(exportOutput, awsassetinventory.ExportFileName(rt, "."+exportFormat))
+		if err := writeOutput(path, func(w io.Writer) error {
+			return writeExport(w, resources[start:end])
+		}); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// writeExport writes resources with the --columns, or the default columns
+// for those resources.
+func writeExport(w io.Writer, resources []awsassetinventory.Resource) error {
+	columns := awsassetinventory.DefaultExportColumns(resources)
+	if len(exportColumns) > 0 {
+		var err error
+		columns, err = awsassetinventory.ParseExportColumns(exportColumns, resources)
+		if err != nil {
+			return err
+		}
+	}
+	return awsassetinventory.WriteCSV(w, columns, resources)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportSplitByType(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		Tags: map[string]string{"owner": "ops"}})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		Tags: map[string]string{"env": "prod"}})
+
+	// Save original values
+	origInput, origSnapshot, origStore, origFormat := exportInput, exportSnapshot, exportStore, exportFormat
+	origOutput, origColumns, origSplit := exportOutput, exportColumns, exportSplitByType
+	t.Cleanup(func() {
+		exportInput, exportSnapshot, exportStore, exportFormat = origInput, origSnapshot, origStore, origFormat
+		exportOutput, exportColumns, exportSplitByType = origOutput, origColumns, origSplit
+	})
+
+	exportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	exportSnapshot = ""
+	exportStore = ""
+	exportFormat = "csv"
+	exportOutput = filepath.Join(tmpDir, "exports")
+	exportColumns = []string{"id", "tags.*"}
+	exportSplitByType = true
+
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport failed: %v", err)
+	}
+
+	for file, want := range map[string]string{
+		"AWS_S3_Bucket.csv":    "id,tags.owner\nlogs,ops\n",
+		"AWS_EC2_Instance.csv": "id,tags.env\ni-1,prod\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(exportOutput, file))
+		if err != nil {
+			t.Fatalf("expected %s: %v", file, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", file, data, want)
+		}
+	}
+}
+
+func TestExportInvalidOptions(t *testing.T) {
+	// Save original values
+	origFormat, origOutput, origColumns, origSplit := exportFormat, exportOutput, exportColumns, exportSplitByType
+	t.Cleanup(func() {
+		exportFormat, exportOutput, exportColumns, exportSplitByType = origFormat, origOutput, origColumns, origSplit
+	})
+
+	exportFormat = "tsv"
+	if err := runExport(nil, nil); err == nil {
+		t.Error("runExport should reject an unknown format")
+	}
+
+	exportFormat = "csv"
+	exportSplitByType = true
+	exportOutput = ""
+	if err := runExport(nil, nil); err == nil {
+		t.Error("runExport should require --output with --split-by-type")
+	}
+
+	exportSplitByType = false
+	exportColumns = []string{"bogus"}
+	if err := runExport(nil, nil); err == nil {
+		t.Error("runExport should reject an unknown column")
+	}
+}
+
+func TestExportXLSX(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+
+	// Save original values
+	origInput, origSnapshot, origStore, origFormat := exportInput, exportSnapshot, exportStore, exportFormat
+	origOutput, origColumns, origSplit := exportOutput, exportColumns, exportSplitByType
+	t.Cleanup(func() {
+		exportInput, exportSnapshot, exportStore, exportFormat = origInput, origSnapshot, origStore, origFormat
+		exportOutput, exportColumns, exportSplitByType = origOutput, origColumns, origSplit
+	})
+
+	exportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	exportSnapshot = ""
+	exportStore = ""
+	exportFormat = "xlsx"
+	exportOutput = filepath.Join(tmpDir, "inventory.xlsx")
+	exportColumns = nil
+	exportSplitByType = true
+
+	if err := runExport(nil, nil); err == nil {
+		t.Error("runExport should reject --split-by-type for xlsx")
+	}
+
+	exportSplitByType = false
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport failed: %v", err)
+	}
+
+	f, err := excelize.OpenFile(exportOutput)
+	if err != nil {
+		t.Fatalf("output is not a workbook: %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); len(sheets) != 3 || sheets[2] != "S3 Bucket" {
+		t.Errorf("sheets = %v", sheets)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterInput    string
+	filterSnapshot string
+	filterStore    string
+	filterOutput   string
+)
+
+var filterCmd = &cobra.Command{
+	Use:     "filter <expression>",
+	Aliases: []string{"query"},
+	Short:   "Select resources from an inventory with an expression",
+	Long: `Select the resources of an inventory that match an expression and write
+them as a new inventory JSON file, which report and the other commands accept.
+
+Expressions compare resource fields with values and combine the comparisons
+with &&, || and !:
+
+  type == "AWS::EC2::Instance" && config.instanceType startsWith "m5" && tags.env == "prod"
+
+Fields are type, id, name, region, availabilityZone, account, arn, source,
+tags.<key> and config.<path>, where a path is a series of .key, [index] or ["key"].
+Operators are ==, !=, <, <=, >, >=, contains, startsWith, endsWith, matches
+(regular expression) and in (list membership). A field on its own is true
+when it is present and not empty, so !tags.owner finds untagged resources.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFilter,
+}
+
+func init() {
+	filterCmd.Flags().StringVarP(&filterInput, "input", "i", "", "Input JSON inventory file (- for stdin)")
+	filterCmd.Flags().StringVar(&filterSnapshot, "snapshot", "", `Snapshot ID to filter, or "latest" (requires --store)`)
+	filterCmd.Flags().StringVar(&filterStore, "store", "", "SQLite store to read --snapshot from")
+	filterCmd.Flags().StringVarP(&filterOutput, "output", "o", "", "Output file path (default: stdout)")
+}
+
+func runFilter(cmd *cobra.Command, args []string) error {
+	// Parse first so that a bad expression fails before any loading.
+	if _, err := awsassetinventory.ParseFilter(args[0]); err != nil {
+		return err
+	}
+
+	inventory, err := loadInventory(filterInput, filterSnapshot, filterStore)
+	if err != nil {
+		return err
+	}
+
+	filtered, err := inventory.Filter(args[0])
+	if err != nil {
+		return err
+	}
+	logger.Info("filter applied", "matched", len(filtered.Resources), "total", len(inventory.Resources))
+
+	return writeOutput(filterOutput, func(w io.Writer) error {
+		data, err := filtered.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to serialize JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	})
+}
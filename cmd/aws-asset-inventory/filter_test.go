@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestFilterWritesInventory(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		Tags: map[string]string{"env": "prod"}})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "scratch", Region: "us-east-1"})
+
+	// Save original values
+	origInput, origSnapshot, origStore, origOutput := filterInput, filterSnapshot, filterStore, filterOutput
+	t.Cleanup(func() {
+		filterInput, filterSnapshot, filterStore, filterOutput = origInput, origSnapshot, origStore, origOutput
+	})
+
+	filterInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	filterSnapshot = ""
+	filterStore = ""
+	filterOutput = filepath.Join(tmpDir, "filtered.json")
+
+	if err := runFilter(nil, []string{`tags.env == "prod"`}); err != nil {
+		t.Fatalf("runFilter failed: %v", err)
+	}
+
+	got, err := awsassetinventory.LoadFromFile(filterOutput)
+	if err != nil {
+		t.Fatalf("filtered output is not an inventory: %v", err)
+	}
+	if len(got.Resources) != 1 || got.Resources[0].ResourceID != "logs" {
+		t.Errorf("filtered resources = %+v, want only logs", got.Resources)
+	}
+}
+
+func TestFilterInvalidExpression(t *testing.T) {
+	// Save original values
+	origInput, origOutput := filterInput, filterOutput
+	t.Cleanup(func() { filterInput, filterOutput = origInput, origOutput })
+
+	filterInput = "does-not-exist.json"
+	filterOutput = filepath.Join(t.TempDir(), "filtered.json")
+
+	if err := runFilter(nil, []string{`type = "x"`}); err == nil {
+		t.Fatal("runFilter should reject an invalid expression")
+	}
+	if _, err := os.Stat(filterOutput); !os.IsNotExist(err) {
+		t.Error("runFilter should not write output for an invalid expression")
+	}
+}
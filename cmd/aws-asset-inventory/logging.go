@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	logLevel  string
+	logFormat string
+
+	// logger is the CLI's diagnostic logger. It writes to stderr and is
+	// reconfigured from --log-level and --log-format before each command runs.
+	logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentPreRunE = configureLogging
+}
+
+func configureLogging(cmd *cobra.Command, args []string) error {
+	l, err := newLogger(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		return err
+	}
+	logger = l
+	slog.SetDefault(l)
+	return nil
+}
+
+// newLogger builds a slog.Logger writing to w at the given level and format.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		wantErr bool
+	}{
+		{"text info", "info", "text", false},
+		{"json debug", "debug", "json", false},
+		{"upper case", "WARN", "JSON", false},
+		{"invalid level", "verbose", "text", true},
+		{"invalid format", "info", "xml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newLogger(new(bytes.Buffer), tt.level, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewLogger_JSONIsMachineParseable(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l, err := newLogger(buf, "info", "json")
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	l.Debug("hidden")
+	l.Info("inventory written", "path", "inventory.json")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("logger wrote %d lines, want 1 (debug suppressed at info level)", len(lines))
+	}
+
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("log line is not valid JSON: %v", err)
+	}
+	if rec["msg"] != "inventory written" || rec["path"] != "inventory.json" {
+		t.Errorf("log record = %v, want msg and path attributes", rec)
+	}
+}
+
+func TestRootRejectsInvalidLogFormat(t *testing.T) {
+	origFormat := logFormat
+	t.Cleanup(func() {
+		logFormat = origFormat
+		rootCmd.SetArgs(nil)
+	})
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs([]string{"version", "--log-format", "xml"})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("root command should reject an invalid --log-format")
+	}
+}
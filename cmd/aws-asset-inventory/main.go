@@ -26,6 +26,16 @@ func init() {
 	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(permissionsCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(snapshotsCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(driftCmd)
+	rootCmd.AddCommand(filterCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(tagsCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(mergeCmd)
 }
 
 func main() {
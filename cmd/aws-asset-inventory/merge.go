@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var mergeOutput string
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <inventory.json> <inventory.json>...",
+	Short: "Combine several inventories into one",
+	Long: `Combine inventory JSON files, such as ones collected per account in
+parallel, into a single inventory that report and the other commands accept.
+
+Regions are united and resources are de-duplicated by ARN, or by type, region
+and ID when they have none. When a resource appears in more than one file, the
+copy from the most recently collected inventory wins. The merged inventory
+lists every input under sources, with its profile, accounts, regions and
+collection time, and every resource records the source it came from, so
+reports can show where each resource was collected.
+
+If any input is counts-only, so is the merged inventory: the resources of the
+other inputs are counted by type and region.`,
+	Example: `  aws-asset-inventory merge prod.json staging.json dev.json --output all.json
+  aws-asset-inventory merge accounts/*.json -o all.json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "Output file path (default: stdout)")
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	inputs, err := loadMergeInputs(args)
+	if err != nil {
+		return err
+	}
+
+	merged := awsassetinventory.Merge(inputs...)
+	if merged.CountsOnly {
+		logger.Info("inventories merged",
+			"inventories", len(inputs),
+			"resources", merged.ResourceCount(),
+			"counts_only", true)
+	} else {
+		total := 0
+		for _, in := range inputs {
+			total += len(in.Inventory.Resources)
+		}
+		logger.Info("inventories merged",
+			"inventories", len(inputs),
+			"resources", len(merged.Resources),
+			"duplicates", total-len(merged.Resources))
+	}
+
+	return writeOutput(mergeOutput, func(w io.Writer) error {
+		data, err := merged.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to serialize JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	})
+}
+
+// loadMergeInputs loads the inventory files at paths, named by their paths.
+func loadMergeInputs(paths []string) ([]awsassetinventory.MergeInput, error) {
+	inputs := make([]awsassetinventory.MergeInput, 0, len(paths))
+	for _, path := range paths {
+		inv, err := awsassetinventory.LoadFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load inventory %s: %w", path, err)
+		}
+		inputs = append(inputs, awsassetinventory.MergeInput{Name: path, Inventory: inv})
+	}
+	return inputs, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestMergeWritesInventory(t *testing.T) {
+	tmpDir := t.TempDir()
+	prod := awsassetinventory.NewInventory("prod", []awsassetinventory.Region{"us-east-1"})
+	prod.CollectedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	prod.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", AccountID: "111111111111"})
+
+	shared := awsassetinventory.NewInventory("shared", []awsassetinventory.Region{"us-east-1", "eu-west-1"})
+	shared.CollectedAt = time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	shared.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		ARN: "arn:aws:s3:::logs", AccountID: "111111111111", ResourceName: "logs-renamed"})
+	shared.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "assets", Region: "eu-west-1",
+		ARN: "arn:aws:s3:::assets", AccountID: "222222222222"})
+
+	// Save original values
+	origOutput := mergeOutput
+	t.Cleanup(func() { mergeOutput = origOutput })
+
+	prodPath := writeInventoryFile(t, tmpDir, "prod.json", prod)
+	sharedPath := writeInventoryFile(t, tmpDir, "shared.json", shared)
+	mergeOutput = filepath.Join(tmpDir, "all.json")
+
+	if err := runMerge(nil, []string{prodPath, sharedPath}); err != nil {
+		t.Fatalf("runMerge failed: %v", err)
+	}
+
+	got, err := awsassetinventory.LoadFromFile(mergeOutput)
+	if err != nil {
+		t.Fatalf("merged output is not an inventory: %v", err)
+	}
+	if len(got.Resources) != 2 {
+		t.Fatalf("merged resources = %d, want 2", len(got.Resources))
+	}
+	if got.Resources[0].ResourceName != "logs-renamed" || got.Resources[0].Source != sharedPath {
+		t.Errorf("duplicate resource = %+v, want the newer copy from %s", got.Resources[0], sharedPath)
+	}
+	if len(got.Sources) != 2 || got.Sources[0].Name != prodPath || got.Sources[1].Name != sharedPath {
+		t.Errorf("merged sources = %+v, want %s and %s", got.Sources, prodPath, sharedPath)
+	}
+}
+
+func TestMergeMissingInput(t *testing.T) {
+	// Save original values
+	origOutput := mergeOutput
+	t.Cleanup(func() { mergeOutput = origOutput })
+
+	mergeOutput = filepath.Join(t.TempDir(), "all.json")
+	if err := runMerge(nil, []string{"does-not-exist.json", "also-missing.json"}); err == nil {
+		t.Fatal("runMerge should return error for a missing input file")
+	}
+}
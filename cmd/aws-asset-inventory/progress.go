@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type regionProgress struct {
+	status     string
+	typesTotal int
+	typesDone  int
+	resources  int
+	throttles  int
+}
+
+// liveProgress redraws one status line per region in place each time an
+// event arrives. It must only be used when w is a terminal, and other output
+// to that terminal must go through its Write so the redraw stays intact.
+type liveProgress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	regions []awsassetinventory.Region
+	state   map[awsassetinventory.Region]*regionProgress
+	drawn   int
+	done    bool
+}
+
+func newLiveProgress(w io.Writer, regions []awsassetinventory.Region) *liveProgress {
+	p := &liveProgress{
+		w:       w,
+		regions: regions,
+		state:   make(map[awsassetinventory.Region]*regionProgress, len(regions)),
+	}
+	for _, r := range regions {
+		p.state[r] = &regionProgress{status: "waiting"}
+	}
+	p.render()
+	return p
+}
+
+func (p *liveProgress) OnEvent(e awsassetinventory.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rp, ok := p.state[e.Region]
+	if !ok {
+		return
+	}
+
+	switch e.Kind {
+	case awsassetinventory.EventRegionStarted:
+		rp.status = "collecting"
+	case awsassetinventory.EventTypesDiscovered:
+		rp.typesTotal = e.Count
+	case awsassetinventory.EventTypeCollected:
+		rp.typesDone++
+		rp.resources += e.Count
+	case awsassetinventory.EventThrottled:
+		rp.throttles++
+	case awsassetinventory.EventRegionCompleted:
+		rp.resources = e.Count
+		rp.status = "done"
+		if e.Err != nil {
+			rp.status = "failed"
+		}
+	default:
+		return
+	}
+
+	p.render()
+}
+
+// Write prints b above the status lines, which are then drawn again below
+// it. Once the display is finished, b is written as is.
+func (p *liveProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done {
+		return p.w.Write(b)
+	}
+	if p.drawn > 0 {
+		fmt.Fprintf(p.w, "\x1b[%dA\x1b[J", p.drawn)
+		p.drawn = 0
+	}
+	n, err := p.w.Write(b)
+	p.render()
+	return n, err
+}
+
+// Finish marks the regions that were never started, such as after the run
+// was cancelled, as skipped and draws the final status lines.
+func (p *liveProgress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, rp := range p.state {
+		if rp.status == "waiting" {
+			rp.status = "skipped"
+		}
+	}
+	p.render()
+	p.done = true
+}
+
+func (p *liveProgress) render() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.w, "\x1b[%dA", p.drawn)
+	}
+	for _, r := range p.regions {
+		fmt.Fprintf(p.w, "\x1b[2K%s\n", formatRegionProgress(r, p.state[r]))
+	}
+	p.drawn = len(p.regions)
+}
+
+func formatRegionProgress(region awsassetinventory.Region, rp *regionProgress) string {
+	line := fmt.Sprintf("%-16s %-10s", region, rp.status)
+	if rp.typesTotal > 0 {
+		if rp.typesDone > 0 {
+			line += fmt.Sprintf(" %4d/%-4d types", rp.typesDone, rp.typesTotal)
+		} else {
+			line += fmt.Sprintf(" %9d types", rp.typesTotal)
+		}
+		line += fmt.Sprintf(" %8d resources", rp.resources)
+	}
+	if rp.throttles > 0 {
+		line += fmt.Sprintf("  (%d throttled)", rp.throttles)
+	}
+	return line
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestLiveProgress(t *testing.T) {
+	buf := new(bytes.Buffer)
+	regions := []awsassetinventory.Region{"us-east-1", "us-west-2"}
+	p := newLiveProgress(buf, regions)
+
+	if strings.Count(buf.String(), "waiting") != 2 {
+		t.Fatalf("initial render should show both regions waiting, got %q", buf.String())
+	}
+
+	buf.Reset()
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionStarted, Region: "us-east-1"})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventTypesDiscovered, Region: "us-east-1", Count: 4})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventTypeCollected, Region: "us-east-1", Count: 7})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventThrottled, Region: "us-east-1"})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionCompleted, Region: "us-west-2", Err: errors.New("denied")})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionStarted, Region: "eu-west-1"})
+
+	output := buf.String()
+	if !strings.Contains(output, "\x1b[2A") {
+		t.Error("liveProgress should move the cursor back up to redraw in place")
+	}
+
+	east := formatRegionProgress("us-east-1", p.state["us-east-1"])
+	for _, want := range []string{"collecting", "1/4", "7 resources", "(1 throttled)"} {
+		if !strings.Contains(east, want) {
+			t.Errorf("us-east-1 line %q should contain %q", east, want)
+		}
+	}
+	if !strings.Contains(formatRegionProgress("us-west-2", p.state["us-west-2"]), "failed") {
+		t.Error("us-west-2 line should show failed")
+	}
+	if _, ok := p.state["eu-west-1"]; ok {
+		t.Error("liveProgress should ignore events for unknown regions")
+	}
+}
+
+func TestLiveProgressWrite(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := newLiveProgress(buf, []awsassetinventory.Region{"us-east-1", "us-west-2"})
+
+	buf.Reset()
+	if _, err := p.Write([]byte("level=WARN msg=\"failed to load AWS config\"\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "\x1b[2A\x1b[J") {
+		t.Errorf("Write should clear the status lines first, got %q", output)
+	}
+	logAt := strings.Index(output, "failed to load AWS config")
+	statusAt := strings.Index(output, "us-east-1")
+	if logAt < 0 || statusAt < logAt {
+		t.Errorf("Write should print its output above the redrawn status lines, got %q", output)
+	}
+
+	buf.Reset()
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionStarted, Region: "us-east-1"})
+	if !strings.HasPrefix(buf.String(), "\x1b[2A") {
+		t.Errorf("redraw after Write should move up over the new status lines only, got %q", buf.String())
+	}
+}
+
+func TestLiveProgressFinish(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := newLiveProgress(buf, []awsassetinventory.Region{"us-east-1", "us-west-2"})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionStarted, Region: "us-east-1"})
+	p.OnEvent(awsassetinventory.Event{Kind: awsassetinventory.EventRegionCompleted, Region: "us-east-1", Count: 3})
+
+	p.Finish()
+
+	if got := p.state["us-west-2"].status; got != "skipped" {
+		t.Errorf("unstarted region status = %q, want skipped", got)
+	}
+	if got := p.state["us-east-1"].status; got != "done" {
+		t.Errorf("finished region status = %q, want done", got)
+	}
+
+	buf.Reset()
+	if _, err := p.Write([]byte("collection complete\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if buf.String() != "collection complete\n" {
+		t.Errorf("Write after Finish should not redraw, got %q", buf.String())
+	}
+}
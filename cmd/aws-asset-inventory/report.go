@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
 	"github.com/spf13/cobra"
@@ -12,37 +15,142 @@ var (
 	reportInput          string
 	reportOutput         string
 	reportIncludeDetails bool
+	reportSnapshot       string
+	reportStore          string
+	reportFormat         string
+	reportTemplate       string
+	reportGroupBy        string
+	reportGroupByTag     string
+	reportDetailColumns  string
+	reportFullARNs       bool
+	reportIncludeConfig  bool
 )
 
 var reportCmd = &cobra.Command{
 	Use:   "report",
-	Short: "Generate a markdown report from inventory JSON",
-	Long: `Generate a markdown report from a previously collected inventory JSON file.
-The report includes resource counts by type and region.`,
+	Short: "Generate a markdown, HTML, JSON or text report from inventory JSON",
+	Long: `Generate a markdown report from a previously collected inventory JSON file,
+or from a snapshot in a SQLite store. The report includes resource counts by
+type and region.
+
+--group-by replaces the counts by region with counts nested by a list of
+account, region, az and type, outermost first. Grouping by account adds a
+matrix of resource types by account, and grouping by az a table per region
+of resources in each availability zone, to check they are evenly spread.
+
+--group-by-tag adds a section for every value of a tag key, such as team or
+cost-center, with its counts by resource type and its resources, and one
+for the resources without the tag.
+
+With --include-details, --detail-columns adds configuration or tag columns
+per resource type from a JSON mapping file, --full-arns shows ARNs in full,
+and --include-config adds each resource's configuration as a collapsible
+JSON block.
+
+With --format json the report is a machine-readable summary of the same
+counts, and with --format text it is plain text with aligned columns.
+
+With --format html the report is a single self-contained HTML file with
+charts of resources by type and region, sortable tables and, with
+--include-details, a searchable resource table with expandable configuration.
+
+With --template the report is rendered from your own Go template instead.
+Templates ending in .html or .htm use html/template; all others use
+text/template. See the README for the data model and helper functions.`,
 	RunE: runReport,
 }
 
 func init() {
-	reportCmd.Flags().StringVarP(&reportInput, "input", "i", "", "Input JSON inventory file (required)")
+	reportCmd.Flags().StringVarP(&reportInput, "input", "i", "", "Input JSON inventory file (- for stdin)")
 	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file path (default: stdout)")
 	reportCmd.Flags().BoolVar(&reportIncludeDetails, "include-details", false, "Include resource details in report")
-
-	_ = reportCmd.MarkFlagRequired("input")
+	reportCmd.Flags().StringVar(&reportSnapshot, "snapshot", "", `Snapshot ID to report on, or "latest" (requires --store)`)
+	reportCmd.Flags().StringVar(&reportStore, "store", "", "SQLite store to read --snapshot from")
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "markdown", "Report format: "+strings.Join(awsassetinventory.RendererNames(), ", "))
+	reportCmd.Flags().StringVar(&reportTemplate, "template", "", "Go template file to render the report with")
+	reportCmd.Flags().StringVar(&reportGroupBy, "group-by", "", "Nest counts by a comma-separated list of account, region, az and type")
+	reportCmd.Flags().StringVar(&reportGroupByTag, "group-by-tag", "", "Add counts and resources for every value of this tag key")
+	reportCmd.Flags().StringVar(&reportDetailColumns, "detail-columns", "", "JSON file mapping resource types to extra detail columns")
+	reportCmd.Flags().BoolVar(&reportFullARNs, "full-arns", false, "Show full ARNs in resource details")
+	reportCmd.Flags().BoolVar(&reportIncludeConfig, "include-config", false, "Add collapsible configuration JSON to resource details")
 }
 
 func runReport(cmd *cobra.Command, args []string) error {
-	data, err := os.ReadFile(reportInput)
-	if err != nil {
-		return fmt.Errorf("failed to read input file: %w", err)
+	if _, ok := awsassetinventory.LookupRenderer(reportFormat); !ok {
+		return fmt.Errorf("invalid format %q: must be one of %s", reportFormat, strings.Join(awsassetinventory.RendererNames(), ", "))
+	}
+	if reportTemplate != "" && reportFormat != awsassetinventory.FormatMarkdown {
+		return fmt.Errorf("--template and --format cannot be used together")
 	}
 
-	inventory, err := awsassetinventory.LoadFromJSON(data)
+	var groupBy []awsassetinventory.GroupKey
+	if reportGroupBy != "" {
+		if reportTemplate != "" || reportFormat != awsassetinventory.FormatMarkdown {
+			return fmt.Errorf("--group-by is only supported by the markdown format")
+		}
+		var err error
+		groupBy, err = awsassetinventory.ParseGroupBy(reportGroupBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	if reportGroupByTag != "" && (reportTemplate != "" || reportFormat != awsassetinventory.FormatMarkdown) {
+		return fmt.Errorf("--group-by-tag is only supported by the markdown format")
+	}
+
+	var detailColumns awsassetinventory.DetailColumns
+	if reportDetailColumns != "" || reportFullARNs || reportIncludeConfig {
+		if !reportIncludeDetails {
+			return fmt.Errorf("--detail-columns, --full-arns and --include-config require --include-details")
+		}
+		if reportTemplate != "" || reportFormat != awsassetinventory.FormatMarkdown {
+			return fmt.Errorf("--detail-columns, --full-arns and --include-config are only supported by the markdown format")
+		}
+		if reportDetailColumns != "" {
+			var err error
+			detailColumns, err = awsassetinventory.LoadDetailColumns(reportDetailColumns)
+			if err != nil {
+				return fmt.Errorf("failed to load detail columns: %w", err)
+			}
+		}
+	}
+
+	var tmpl *awsassetinventory.ReportTemplate
+	if reportTemplate != "" {
+		var err error
+		tmpl, err = awsassetinventory.ParseReportTemplate(reportTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to load template: %w", err)
+		}
+	}
+
+	inventory, err := loadInventory(reportInput, reportSnapshot, reportStore)
 	if err != nil {
-		return fmt.Errorf("failed to parse inventory JSON: %w", err)
+		return err
+	}
+
+	if reportGroupByTag != "" && !inventory.CountsOnly && len(inventory.Resources) > 0 && !inventory.HasTags() {
+		logger.Warn("no resource in the inventory has tags; the By Tag section will be empty",
+			"tag", reportGroupByTag)
 	}
 
-	rg := awsassetinventory.NewReportGenerator(inventory)
-	rg.IncludeDetails = reportIncludeDetails
+	var rg interface{ Generate(io.Writer) error }
+	if tmpl != nil {
+		tg := awsassetinventory.NewTemplateReportGenerator(inventory, tmpl)
+		tg.IncludeDetails = reportIncludeDetails
+		rg = tg
+	} else {
+		mg := awsassetinventory.NewReportGenerator(inventory)
+		mg.IncludeDetails = reportIncludeDetails
+		mg.Format = reportFormat
+		mg.GroupBy = groupBy
+		mg.GroupByTag = reportGroupByTag
+		mg.DetailColumns = detailColumns
+		mg.FullARNs = reportFullARNs
+		mg.IncludeConfig = reportIncludeConfig
+		rg = mg
+	}
 
 	if reportOutput == "" || reportOutput == "-" {
 		return rg.Generate(os.Stdout)
@@ -58,6 +166,40 @@ func runReport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Fprintf(os.Stderr, "Report written to: %s\n", reportOutput)
+	logger.Info("report written", "path", reportOutput)
 	return nil
 }
+
+// loadInventory reads the inventory from the JSON file at input, or from
+// stdin when input is "-", or the snapshot with the given ID (or "latest")
+// from the SQLite store.
+func loadInventory(input, snapshot, store string) (*awsassetinventory.Inventory, error) {
+	switch {
+	case input != "" && snapshot != "":
+		return nil, fmt.Errorf("--input and --snapshot cannot be used together")
+	case snapshot != "":
+		if store == "" {
+			return nil, fmt.Errorf("--snapshot requires --store")
+		}
+		return loadSnapshot(context.Background(), store, snapshot)
+	case input == "":
+		return nil, fmt.Errorf("one of --input or --snapshot is required")
+	}
+
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	inventory, err := awsassetinventory.LoadFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inventory JSON: %w", err)
+	}
+	return inventory, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -182,3 +183,239 @@ func TestReportWithDetails(t *testing.T) {
 		t.Error("report with --include-details should contain resource ID")
 	}
 }
+
+func TestReportHTMLFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+
+	// Save original values
+	origInput, origOutput, origDetails, origFormat := reportInput, reportOutput, reportIncludeDetails, reportFormat
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportIncludeDetails, reportFormat = origInput, origOutput, origDetails, origFormat
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.html")
+	reportIncludeDetails = true
+	reportFormat = "html"
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "<td>logs</td>") {
+		t.Errorf("report should be HTML with resource details, got:\n%s", data)
+	}
+
+	reportFormat = "pdf"
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should reject an unknown format")
+	}
+}
+
+func TestReportTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+
+	tmplPath := filepath.Join(tmpDir, "summary.tmpl")
+	if err := os.WriteFile(tmplPath, []byte(`{{.Profile}}:{{range .CountsByType}} {{.ResourceType}}={{.Count}}{{end}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save original values
+	origInput, origOutput, origFormat, origTemplate := reportInput, reportOutput, reportFormat, reportTemplate
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportFormat, reportTemplate = origInput, origOutput, origFormat, origTemplate
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.txt")
+	reportFormat = "markdown"
+	reportTemplate = tmplPath
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "test: AWS::S3::Bucket=1" {
+		t.Errorf("report = %q", got)
+	}
+
+	reportFormat = "html"
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should reject --template with --format html")
+	}
+
+	reportFormat = "markdown"
+	reportTemplate = filepath.Join(tmpDir, "missing.tmpl")
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should fail for a missing template")
+	}
+}
+
+func TestReportJSONFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+
+	// Save original values
+	origInput, origOutput, origFormat := reportInput, reportOutput, reportFormat
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportFormat = origInput, origOutput, origFormat
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.json")
+	reportFormat = "json"
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary awsassetinventory.ReportSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("report should be JSON: %v", err)
+	}
+	if summary.Total != 1 || summary.ByType["AWS::S3::Bucket"] != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestReportGroupBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1", AvailabilityZone: "us-east-1a", AccountID: "111111111111"})
+
+	// Save original values
+	origInput, origOutput, origFormat, origGroupBy := reportInput, reportOutput, reportFormat, reportGroupBy
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportFormat, reportGroupBy = origInput, origOutput, origFormat, origGroupBy
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.md")
+	reportFormat = "markdown"
+	reportGroupBy = "account,az"
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## By Account\n", "## By Account > Availability Zone\n", "## By Availability Zone\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report should contain %q", want)
+		}
+	}
+
+	reportGroupBy = "owner"
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should reject an unknown group key")
+	}
+
+	reportGroupBy = "region"
+	reportFormat = "html"
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should reject --group-by with --format html")
+	}
+}
+
+func TestReportGroupByTag(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1", Tags: map[string]string{"team": "data"}})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "tmp", Region: "us-east-1"})
+
+	// Save original values
+	origInput, origOutput, origFormat, origGroupByTag := reportInput, reportOutput, reportFormat, reportGroupByTag
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportFormat, reportGroupByTag = origInput, origOutput, origFormat, origGroupByTag
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.md")
+	reportFormat = "markdown"
+	reportGroupByTag = "team"
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## By Tag: team\n", "### team = data (1)\n", "### Untagged (1)\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report should contain %q", want)
+		}
+	}
+
+	reportFormat = "json"
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should reject --group-by-tag with --format json")
+	}
+}
+
+func TestReportDetailColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "us-east-1",
+		Configuration: json.RawMessage(`{"instanceType":"t3.micro"}`)})
+
+	columnsPath := filepath.Join(tmpDir, "columns.json")
+	if err := os.WriteFile(columnsPath, []byte(`{"AWS::EC2::Instance": [{"name": "Type", "field": "config.instanceType"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save original values
+	origInput, origOutput, origDetails, origFormat := reportInput, reportOutput, reportIncludeDetails, reportFormat
+	origColumns, origFullARNs, origConfig := reportDetailColumns, reportFullARNs, reportIncludeConfig
+	t.Cleanup(func() {
+		reportInput, reportOutput, reportIncludeDetails, reportFormat = origInput, origOutput, origDetails, origFormat
+		reportDetailColumns, reportFullARNs, reportIncludeConfig = origColumns, origFullARNs, origConfig
+	})
+
+	reportInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	reportOutput = filepath.Join(tmpDir, "report.md")
+	reportFormat = "markdown"
+	reportDetailColumns = columnsPath
+	reportIncludeConfig = true
+
+	reportIncludeDetails = false
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should require --include-details")
+	}
+
+	reportIncludeDetails = true
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| Name | ID | Region | ARN | Type |\n", "| - | i-1 | us-east-1 | - | t3.micro |\n", "<details><summary>i-1</summary>"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report should contain %q, got:\n%s", want, data)
+		}
+	}
+
+	reportDetailColumns = filepath.Join(tmpDir, "missing.json")
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should fail for a missing columns file")
+	}
+}
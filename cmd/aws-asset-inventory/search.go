@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchInput    string
+	searchSnapshot string
+	searchStore    string
+	searchRegex    bool
+	searchLimit    int
+	searchFormat   string
+	searchOutput   string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Find resources mentioning a term",
+	Long: `Search resource IDs, names, ARNs, tags and every configuration value for a
+term, such as an IP address or a security group ID, and list the matching
+resources best first with the fields that matched.
+
+Every word of the term must match. A word matches a value containing it
+whole or, with a lower score, a word starting with it. With --regex the term
+is a regular expression matched against each field instead.`,
+	Example: `  aws-asset-inventory search --input inventory.json 10.12.4.7
+  aws-asset-inventory search --input inventory.json --regex 'sg-0abc[0-9a-f]+'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchInput, "input", "i", "", "Input JSON inventory file (- for stdin)")
+	searchCmd.Flags().StringVar(&searchSnapshot, "snapshot", "", `Snapshot ID to search, or "latest" (requires --store)`)
+	searchCmd.Flags().StringVar(&searchStore, "store", "", "SQLite store to read --snapshot from")
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat the term as a regular expression")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of results (0 for all)")
+	searchCmd.Flags().StringVarP(&searchFormat, "format", "f", "text", "Output format: text or json")
+	searchCmd.Flags().StringVarP(&searchOutput, "output", "o", "", "Output file path (default: stdout)")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	if searchFormat != "text" && searchFormat != "json" {
+		return fmt.Errorf("invalid format %q: must be text or json", searchFormat)
+	}
+	if searchLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	var re *regexp.Regexp
+	if searchRegex {
+		var err error
+		re, err = regexp.Compile(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+
+	inventory, err := loadInventory(searchInput, searchSnapshot, searchStore)
+	if err != nil {
+		return err
+	}
+
+	index := awsassetinventory.NewSearchIndex(inventory.Resources)
+	var results []awsassetinventory.SearchResult
+	if re != nil {
+		results = index.SearchRegexp(re)
+	} else {
+		results = index.Search(args[0])
+	}
+	logger.Debug("search complete", "resources", index.Len(), "matches", len(results))
+
+	total := len(results)
+	if searchLimit > 0 && searchLimit < len(results) {
+		results = results[:searchLimit]
+	}
+
+	return writeOutput(searchOutput, func(w io.Writer) error {
+		if searchFormat == "json" {
+			if results == nil {
+				results = []awsassetinventory.SearchResult{}
+			}
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON: %w", err)
+			}
+			_, err = fmt.Fprintln(w, string(data))
+			return err
+		}
+		return writeSearchResults(w, results, total)
+	})
+}
+
+// writeSearchResults prints a line per result followed by its matched
+// fields, indented.
+func writeSearchResults(w io.Writer, results []awsassetinventory.SearchResult, total int) error {
+	if total == 0 {
+		_, err := fmt.Fprintln(w, "No matching resources.")
+		return err
+	}
+
+	for _, r := range results {
+		res := r.Resource
+		title := res.ResourceID
+		if res.ResourceName != "" && res.ResourceName != res.ResourceID {
+			title = fmt.Sprintf("%s (%s)", res.ResourceID, res.ResourceName)
+		}
+		if _, err := fmt.Fprintf(w, "%s %s [%s] score %.1f\n", res.ResourceType, title, res.Region, r.Score); err != nil {
+			return err
+		}
+		for _, m := range r.Matches {
+			if _, err := fmt.Fprintf(w, "    %s: %s\n", m.Path, m.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(results) < total {
+		_, err := fmt.Fprintf(w, "\nShowing %d of %d matching resources; use --limit to see more.\n", len(results), total)
+		return err
+	}
+	return nil
+}
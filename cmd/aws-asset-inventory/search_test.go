@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestSearchTextOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	for _, id := range []string{"sg-0abc1", "sg-0abc2", "sg-0def"} {
+		inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::EC2::SecurityGroup", ResourceID: id, Region: "us-east-1"})
+	}
+
+	// Save original values
+	origInput, origSnapshot, origStore := searchInput, searchSnapshot, searchStore
+	origRegex, origLimit, origFormat, origOutput := searchRegex, searchLimit, searchFormat, searchOutput
+	t.Cleanup(func() {
+		searchInput, searchSnapshot, searchStore = origInput, origSnapshot, origStore
+		searchRegex, searchLimit, searchFormat, searchOutput = origRegex, origLimit, origFormat, origOutput
+	})
+
+	searchInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	searchSnapshot = ""
+	searchStore = ""
+	searchRegex = false
+	searchLimit = 1
+	searchFormat = "text"
+	searchOutput = filepath.Join(tmpDir, "results.txt")
+
+	if err := runSearch(nil, []string{"sg-0abc"}); err != nil {
+		t.Fatalf("runSearch failed: %v", err)
+	}
+
+	data, err := os.ReadFile(searchOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(data)
+	for _, want := range []string{
+		"AWS::EC2::SecurityGroup sg-0abc1 [us-east-1]",
+		"    resourceId: sg-0abc1",
+		"Showing 1 of 2 matching resources",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestSearchInvalidOptions(t *testing.T) {
+	// Save original values
+	origRegex, origFormat := searchRegex, searchFormat
+	t.Cleanup(func() { searchRegex, searchFormat = origRegex, origFormat })
+
+	searchRegex = true
+	searchFormat = "text"
+	if err := runSearch(nil, []string{"("}); err == nil || !strings.Contains(err.Error(), "regular expression") {
+		t.Errorf("runSearch should reject an invalid regular expression, got %v", err)
+	}
+
+	searchRegex = false
+	searchFormat = "yaml"
+	if err := runSearch(nil, []string{"x"}); err == nil {
+		t.Error("runSearch should reject an unknown format")
+	}
+}
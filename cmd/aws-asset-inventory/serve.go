@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+// serveShutdownTimeout bounds how long in-flight requests may take to finish
+// after an interrupt.
+const serveShutdownTimeout = 10 * time.Second
+
+var (
+	serveListen      string
+	serveInputs      []string
+	serveSnapshotDir string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve inventory over a read-only HTTP API",
+	Long: `Serve one or more inventory JSON files, or the latest snapshot written by
+the daemon, over a read-only HTTP API with resource queries, counts and the
+markdown report.`,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":8080", "Address to listen on")
+	serveCmd.Flags().StringSliceVarP(&serveInputs, "input", "i", nil, "Inventory JSON file to serve (repeatable)")
+	serveCmd.Flags().StringVar(&serveSnapshotDir, "snapshot-dir", "", "Serve the newest snapshot in this daemon snapshot directory")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	source, err := serveSource()
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", serveListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveListen, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return serveHTTP(ctx, ln, awsassetinventory.NewServer(source))
+}
+
+// serveSource returns the inventory source selected by --input or
+// --snapshot-dir.
+func serveSource() (awsassetinventory.InventorySource, error) {
+	switch {
+	case len(serveInputs) > 0 && serveSnapshotDir != "":
+		return nil, fmt.Errorf("--input and --snapshot-dir cannot be used together")
+	case serveSnapshotDir != "":
+		return awsassetinventory.NewSnapshotDir(serveSnapshotDir, 0).Source(), nil
+	case len(serveInputs) > 0:
+		inputs, err := loadMergeInputs(serveInputs)
+		if err != nil {
+			return nil, err
+		}
+		if len(inputs) == 1 {
+			return awsassetinventory.StaticSource(inputs[0].Inventory), nil
+		}
+		return awsassetinventory.StaticSource(awsassetinventory.Merge(inputs...)), nil
+	default:
+		return nil, fmt.Errorf("one of --input or --snapshot-dir is required")
+	}
+}
+
+// serveHTTP serves handler on ln until ctx is done, then shuts down
+// gracefully.
+func serveHTTP(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	logger.Info("serving inventory", "address", ln.Addr().String())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestServeRequiresSource(t *testing.T) {
+	// Save original values
+	origInputs := serveInputs
+	origSnapshotDir := serveSnapshotDir
+	t.Cleanup(func() {
+		serveInputs = origInputs
+		serveSnapshotDir = origSnapshotDir
+	})
+
+	serveInputs = nil
+	serveSnapshotDir = ""
+	if err := runServe(nil, nil); err == nil {
+		t.Error("runServe should return error without --input or --snapshot-dir")
+	}
+
+	serveInputs = []string{"inventory.json"}
+	serveSnapshotDir = "snapshots"
+	if err := runServe(nil, nil); err == nil {
+		t.Error("runServe should return error when both --input and --snapshot-dir are set")
+	}
+}
+
+func TestServeSourceMergesInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := awsassetinventory.NewInventory("prod", []awsassetinventory.Region{"us-east-1"})
+	a.CollectedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	a.AddResource(awsassetinventory.Resource{ResourceID: "i-1", Region: "us-east-1"})
+
+	b := awsassetinventory.NewInventory("dev", []awsassetinventory.Region{"us-east-1", "eu-west-1"})
+	b.CollectedAt = time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	b.Incomplete = true
+	b.AddResource(awsassetinventory.Resource{ResourceID: "i-2", Region: "eu-west-1"})
+
+	// Save original values
+	origInputs := serveInputs
+	origSnapshotDir := serveSnapshotDir
+	t.Cleanup(func() {
+		serveInputs = origInputs
+		serveSnapshotDir = origSnapshotDir
+	})
+
+	serveInputs = []string{
+		writeInventoryFile(t, tmpDir, "prod.json", a),
+		writeInventoryFile(t, tmpDir, "dev.json", b),
+	}
+	serveSnapshotDir = ""
+
+	source, err := serveSource()
+	if err != nil {
+		t.Fatalf("serveSource returned error: %v", err)
+	}
+	got, err := source()
+	if err != nil {
+		t.Fatalf("source returned error: %v", err)
+	}
+
+	if len(got.Resources) != 2 {
+		t.Errorf("merged resources = %d, want 2", len(got.Resources))
+	}
+	if len(got.Regions) != 2 {
+		t.Errorf("merged regions = %v, want us-east-1 and eu-west-1", got.Regions)
+	}
+	if got.Profile != "prod, dev" {
+		t.Errorf("merged Profile = %q, want %q", got.Profile, "prod, dev")
+	}
+	if len(got.Sources) != 2 || got.Sources[0].Name != serveInputs[0] {
+		t.Errorf("merged Sources = %+v, want one per input named by path", got.Sources)
+	}
+	if !got.Incomplete || got.CountsOnly {
+		t.Errorf("merged Incomplete, CountsOnly = %v, %v, want true, false", got.Incomplete, got.CountsOnly)
+	}
+}
+
+func TestServeSourceCountsOnlyInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	census := awsassetinventory.NewInventory("census", []awsassetinventory.Region{"us-east-1"})
+	census.CountsOnly = true
+	census.AddCount(awsassetinventory.ResourceTypeCount{ResourceType: "AWS::EC2::Instance", Region: "us-east-1", Count: 40})
+
+	full := awsassetinventory.NewInventory("prod", []awsassetinventory.Region{"eu-west-1"})
+	full.AddResource(awsassetinventory.Resource{ResourceType: "AWS::EC2::Instance", ResourceID: "i-1", Region: "eu-west-1"})
+	full.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "eu-west-1"})
+
+	// Save original values
+	origInputs := serveInputs
+	origSnapshotDir := serveSnapshotDir
+	t.Cleanup(func() {
+		serveInputs = origInputs
+		serveSnapshotDir = origSnapshotDir
+	})
+
+	serveInputs = []string{
+		writeInventoryFile(t, tmpDir, "census.json", census),
+		writeInventoryFile(t, tmpDir, "prod.json", full),
+	}
+	serveSnapshotDir = ""
+
+	source, err := serveSource()
+	if err != nil {
+		t.Fatalf("serveSource returned error: %v", err)
+	}
+	srv := httptest.NewServer(awsassetinventory.NewServer(source))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/counts/by-type")
+	if err != nil {
+		t.Fatalf("GET /counts/by-type failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var byType map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&byType); err != nil {
+		t.Fatalf("failed to decode /counts/by-type: %v", err)
+	}
+	if byType["AWS::EC2::Instance"] != 41 || byType["AWS::S3::Bucket"] != 1 {
+		t.Errorf("/counts/by-type = %v, want 41 instances and 1 bucket", byType)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1"})
+	data, err := inv.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save original values
+	origInputs := serveInputs
+	t.Cleanup(func() { serveInputs = origInputs })
+	serveInputs = []string{path}
+
+	source, err := serveSource()
+	if err != nil {
+		t.Fatalf("serveSource() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTP(ctx, ln, awsassetinventory.NewServer(source)) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/counts/by-type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /counts/by-type status = %d, body %s", resp.StatusCode, body)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serveHTTP() error = %v", err)
+	}
+}
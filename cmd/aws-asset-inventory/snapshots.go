@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var snapshotsStore string
+
+var snapshotsCmd = &cobra.Command{
+	Use:   "snapshots",
+	Short: "Inspect inventory snapshots in a SQLite store",
+	Long: `Inspect the inventory snapshots saved with collect --store: list them, or
+run ad-hoc SQL across all of them.`,
+}
+
+var snapshotsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotsList,
+}
+
+var snapshotsSQLCmd = &cobra.Command{
+	Use:   "sql <query>",
+	Short: "Run a read-only SQL query across stored snapshots",
+	Long: `Run a read-only SQL query across all stored snapshots and print the result
+as a table. The query must be a single statement. The store has two tables:
+
+  snapshots  id, collected_at, profile, regions, incomplete, counts_only,
+             resource_count, metadata
+  resources  snapshot_id, resource_type, resource_id, resource_name, region,
+             availability_zone, account_id, arn, configuration, tags,
+             source
+
+configuration and tags are JSON text and can be queried with json_extract.`,
+	Example: `  aws-asset-inventory snapshots sql --store inventory.sqlite \
+    "SELECT MIN(s.collected_at) FROM resources r JOIN snapshots s ON s.id = r.snapshot_id WHERE r.arn = 'arn:aws:s3:::my-bucket'"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotsSQL,
+}
+
+func init() {
+	snapshotsCmd.PersistentFlags().StringVar(&snapshotsStore, "store", "", "SQLite store path (required)")
+	_ = snapshotsCmd.MarkPersistentFlagRequired("store")
+
+	snapshotsCmd.AddCommand(snapshotsListCmd)
+	snapshotsCmd.AddCommand(snapshotsSQLCmd)
+}
+
+func runSnapshotsList(cmd *cobra.Command, args []string) error {
+	store, err := openExistingStore(snapshotsStore)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	snapshots, err := store.Snapshots(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	return writeSnapshotList(os.Stdout, snapshots)
+}
+
+func writeSnapshotList(w io.Writer, snapshots []awsassetinventory.SnapshotInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCOLLECTED\tPROFILE\tREGIONS\tRESOURCES\tSTATUS")
+	for _, s := range snapshots {
+		status := "complete"
+		switch {
+		case s.Incomplete:
+			status = "incomplete"
+		case s.CountsOnly:
+			status = "counts only"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\n", s.ID, s.CollectedAt.Format("2006-01-02 15:04:05 UTC"),
+			s.Profile, strings.Join(regionStrings(s.Regions), ","), s.ResourceCount, status)
+	}
+	return tw.Flush()
+}
+
+func runSnapshotsSQL(cmd *cobra.Command, args []string) error {
+	store, err := openExistingStore(snapshotsStore)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	result, err := store.Query(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(result.Columns, "\t"))
+	for _, row := range result.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// openExistingStore opens a store that must already exist, so a mistyped
+// path is reported instead of silently creating an empty database.
+func openExistingStore(path string) (*awsassetinventory.Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	return awsassetinventory.OpenStore(path)
+}
+
+// loadSnapshot loads the snapshot with the given ID, or the newest one for
+// "latest", from the store at path.
+func loadSnapshot(ctx context.Context, path, snapshot string) (*awsassetinventory.Inventory, error) {
+	store, err := openExistingStore(path)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	var id int64
+	if snapshot == "latest" {
+		if id, err = store.LatestID(ctx); err != nil {
+			return nil, err
+		}
+	} else if id, err = strconv.ParseInt(snapshot, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid snapshot ID %q: must be a number or \"latest\"", snapshot)
+	}
+
+	return store.Load(ctx, id)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func newTestStore(t *testing.T) (string, []int64) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "inventory.sqlite")
+	store, err := awsassetinventory.OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	defer store.Close()
+
+	var ids []int64
+	for i, resourceID := range []string{"i-older", "i-newer"} {
+		inv := awsassetinventory.NewInventory("test-profile", []awsassetinventory.Region{"us-east-1"})
+		inv.CollectedAt = time.Date(2024, 1, 15+i, 10, 0, 0, 0, time.UTC)
+		inv.AddResource(awsassetinventory.Resource{
+			ResourceType: "AWS::EC2::Instance",
+			ResourceID:   resourceID,
+			Region:       "us-east-1",
+		})
+		id, err := store.Save(context.Background(), inv)
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return path, ids
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	path, ids := newTestStore(t)
+
+	inv, err := loadSnapshot(context.Background(), path, "latest")
+	if err != nil {
+		t.Fatalf("loadSnapshot(latest) error = %v", err)
+	}
+	if inv.Resources[0].ResourceID != "i-newer" {
+		t.Errorf("loadSnapshot(latest) = %s, want i-newer", inv.Resources[0].ResourceID)
+	}
+
+	inv, err = loadSnapshot(context.Background(), path, "1")
+	if err != nil || ids[0] != 1 {
+		t.Fatalf("loadSnapshot(1) error = %v", err)
+	}
+	if inv.Resources[0].ResourceID != "i-older" {
+		t.Errorf("loadSnapshot(1) = %s, want i-older", inv.Resources[0].ResourceID)
+	}
+
+	if _, err := loadSnapshot(context.Background(), path, "first"); err == nil {
+		t.Error("loadSnapshot should reject a non-numeric snapshot ID")
+	}
+	if _, err := loadSnapshot(context.Background(), filepath.Join(t.TempDir(), "missing.sqlite"), "latest"); err == nil {
+		t.Error("loadSnapshot should not create a missing store")
+	}
+}
+
+func TestWriteSnapshotList(t *testing.T) {
+	snapshots := []awsassetinventory.SnapshotInfo{
+		{ID: 1, CollectedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Profile: "prod",
+			Regions: []awsassetinventory.Region{"us-east-1", "us-west-2"}, ResourceCount: 42},
+		{ID: 2, CollectedAt: time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC), Profile: "prod",
+			Regions: []awsassetinventory.Region{"us-east-1"}, Incomplete: true, ResourceCount: 7},
+	}
+
+	var buf bytes.Buffer
+	if err := writeSnapshotList(&buf, snapshots); err != nil {
+		t.Fatalf("writeSnapshotList() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("writeSnapshotList() wrote %d lines, want 3", len(lines))
+	}
+	for _, want := range []string{"2024-01-15 10:00:00 UTC", "us-east-1,us-west-2", "42", "complete"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("first row should contain %q, got %q", want, lines[1])
+		}
+	}
+	if !strings.Contains(lines[2], "incomplete") {
+		t.Errorf("second row should be incomplete, got %q", lines[2])
+	}
+}
+
+func TestReportFromSnapshot(t *testing.T) {
+	path, _ := newTestStore(t)
+	outputFile := filepath.Join(t.TempDir(), "report.md")
+
+	// Save original values
+	origSnapshot := reportSnapshot
+	origStore := reportStore
+	origOutput := reportOutput
+	t.Cleanup(func() {
+		reportSnapshot = origSnapshot
+		reportStore = origStore
+		reportOutput = origOutput
+	})
+
+	reportSnapshot = "latest"
+	reportStore = path
+	reportOutput = outputFile
+
+	if err := runReport(nil, nil); err != nil {
+		t.Fatalf("runReport failed: %v", err)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "**Collected:** 2024-01-16 10:00:00 UTC") {
+		t.Errorf("report should be for the latest snapshot, got:\n%s", content)
+	}
+}
+
+func TestReportSnapshotRequiresStore(t *testing.T) {
+	// Save original values
+	origSnapshot := reportSnapshot
+	origStore := reportStore
+	t.Cleanup(func() {
+		reportSnapshot = origSnapshot
+		reportStore = origStore
+	})
+
+	reportSnapshot = "latest"
+	reportStore = ""
+
+	if err := runReport(nil, nil); err == nil {
+		t.Error("runReport should return error when --snapshot is used without --store")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagsInput     string
+	tagsSnapshot  string
+	tagsStore     string
+	tagsPolicy    string
+	tagsFormat    string
+	tagsOutput    string
+	tagsFailUnder float64
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Check resource tags against a tag policy",
+	Long: `Check the tags of every resource in an inventory against a policy file of
+required tag keys and allowed value patterns per resource type, and report the
+non-compliant resources grouped by type, region and account together with
+the compliance percentage. An inventory in which no resource has tags is
+rejected, since it was most likely collected without permission to read them.
+
+With --fail-under the command exits with an error, after writing the report,
+when compliance is below the given percentage, so that CI jobs fail.`,
+	Example: `  aws-asset-inventory tags --input inventory.json --policy tag-policy.json --fail-under 95`,
+	Args:    cobra.NoArgs,
+	RunE:    runTags,
+}
+
+func init() {
+	tagsCmd.Flags().StringVarP(&tagsInput, "input", "i", "", "Input JSON inventory file (- for stdin)")
+	tagsCmd.Flags().StringVar(&tagsSnapshot, "snapshot", "", `Snapshot ID to check, or "latest" (requires --store)`)
+	tagsCmd.Flags().StringVar(&tagsStore, "store", "", "SQLite store to read --snapshot from")
+	tagsCmd.Flags().StringVar(&tagsPolicy, "policy", "", "JSON tag policy file (required)")
+	tagsCmd.Flags().StringVarP(&tagsFormat, "format", "f", "markdown", "Output format: markdown or json")
+	tagsCmd.Flags().StringVarP(&tagsOutput, "output", "o", "", "Output file path (default: stdout)")
+	tagsCmd.Flags().Float64Var(&tagsFailUnder, "fail-under", 0, "Exit with an error when compliance is below this percentage")
+
+	_ = tagsCmd.MarkFlagRequired("policy")
+}
+
+func runTags(cmd *cobra.Command, args []string) error {
+	if tagsFormat != "markdown" && tagsFormat != "json" {
+		return fmt.Errorf("invalid format %q: must be markdown or json", tagsFormat)
+	}
+	if tagsFailUnder < 0 || tagsFailUnder > 100 {
+		return fmt.Errorf("--fail-under must be between 0 and 100")
+	}
+
+	policy, err := awsassetinventory.LoadTagPolicy(tagsPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to load tag policy: %w", err)
+	}
+
+	inventory, err := loadInventory(tagsInput, tagsSnapshot, tagsStore)
+	if err != nil {
+		return err
+	}
+
+	compliance, err := policy.Evaluate(inventory)
+	if err != nil {
+		return err
+	}
+
+	err = writeOutput(tagsOutput, func(w io.Writer) error {
+		if tagsFormat == "json" {
+			data, err := compliance.ToJSON()
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON: %w", err)
+			}
+			_, err = fmt.Fprintln(w, string(data))
+			return err
+		}
+		return awsassetinventory.NewTagReportGenerator(compliance).Generate(w)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("tag compliance checked",
+		"evaluated", compliance.Evaluated,
+		"non_compliant", compliance.NonCompliant,
+		"compliance_percent", compliance.Percent)
+
+	if compliance.Percent < tagsFailUnder {
+		// Round down, as the report does, so that the message never claims
+		// 100.0% for a failing run.
+		return fmt.Errorf("tag compliance %.1f%% is below --fail-under %g%%", math.Floor(compliance.Percent*10)/10, tagsFailUnder)
+	}
+	return nil
+}
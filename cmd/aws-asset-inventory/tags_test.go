@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/aws-asset-inventory/awsassetinventory"
+)
+
+func TestTagsFailUnder(t *testing.T) {
+	tmpDir := t.TempDir()
+	inv := awsassetinventory.NewInventory("test", []awsassetinventory.Region{"us-east-1"})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Region: "us-east-1",
+		Tags: map[string]string{"owner": "ops"}})
+	inv.AddResource(awsassetinventory.Resource{ResourceType: "AWS::S3::Bucket", ResourceID: "scratch", Region: "us-east-1"})
+
+	policyFile := filepath.Join(tmpDir, "policy.json")
+	if err := os.WriteFile(policyFile, []byte(`{"rules":{"*":{"required":["owner"]}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save original values
+	origInput, origSnapshot, origStore, origPolicy := tagsInput, tagsSnapshot, tagsStore, tagsPolicy
+	origFormat, origOutput, origFailUnder := tagsFormat, tagsOutput, tagsFailUnder
+	t.Cleanup(func() {
+		tagsInput, tagsSnapshot, tagsStore, tagsPolicy = origInput, origSnapshot, origStore, origPolicy
+		tagsFormat, tagsOutput, tagsFailUnder = origFormat, origOutput, origFailUnder
+	})
+
+	tagsInput = writeInventoryFile(t, tmpDir, "inventory.json", inv)
+	tagsSnapshot = ""
+	tagsStore = ""
+	tagsPolicy = policyFile
+	tagsFormat = "markdown"
+	tagsOutput = filepath.Join(tmpDir, "tags.md")
+
+	tagsFailUnder = 50
+	if err := runTags(nil, nil); err != nil {
+		t.Fatalf("runTags should pass at exactly the threshold: %v", err)
+	}
+
+	tagsFailUnder = 90
+	err := runTags(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "50.0% is below --fail-under 90%") {
+		t.Fatalf("runTags error = %v, want a --fail-under failure", err)
+	}
+
+	// The report is still written when the threshold fails.
+	data, err := os.ReadFile(tagsOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "| us-east-1 | - | - | scratch | missing \"owner\" |") {
+		t.Errorf("report should list scratch, got:\n%s", data)
+	}
+}
+
+func TestTagsInvalidOptions(t *testing.T) {
+	// Save original values
+	origFormat, origFailUnder := tagsFormat, tagsFailUnder
+	t.Cleanup(func() { tagsFormat, tagsFailUnder = origFormat, origFailUnder })
+
+	tagsFormat = "csv"
+	tagsFailUnder = 0
+	if err := runTags(nil, nil); err == nil {
+		t.Error("runTags should reject an unknown format")
+	}
+
+	tagsFormat = "markdown"
+	tagsFailUnder = 101
+	if err := runTags(nil, nil); err == nil {
+		t.Error("runTags should reject --fail-under above 100")
+	}
+}
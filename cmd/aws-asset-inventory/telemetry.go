@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// telemetryShutdownTimeout bounds how long buffered spans and metrics are
+// flushed for after a command finishes.
+const telemetryShutdownTimeout = 10 * time.Second
+
+var otelEndpoint string
+
+func init() {
+	for _, cmd := range []*cobra.Command{collectCmd, daemonCmd} {
+		cmd.Flags().StringVar(&otelEndpoint, "otel-endpoint", "", "OTLP/HTTP endpoint for traces and metrics, e.g. http://localhost:4318 (default: $OTEL_EXPORTER_OTLP_ENDPOINT)")
+	}
+}
+
+// setupTelemetry installs global OpenTelemetry tracer and meter providers
+// that export over OTLP/HTTP to endpoint. When endpoint is empty the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT variable is used; when neither is set telemetry
+// stays disabled. The returned function flushes and shuts the providers down.
+func setupTelemetry(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	var traceOpts []otlptracehttp.Option
+	var metricOpts []otlpmetrichttp.Option
+	if endpoint != "" {
+		base := strings.TrimRight(endpoint, "/")
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(base+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"))
+	} else if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return noop, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "aws-asset-inventory"),
+		attribute.String("service.version", gitSHA),
+	))
+	if err != nil {
+		return noop, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}, nil
+}
+
+// shutdownTelemetry flushes telemetry with a fresh deadline, since the
+// command's own context may already be cancelled by an interrupt or timeout.
+func shutdownTelemetry(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		logger.Warn("failed to flush telemetry", "error", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupTelemetry_DisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	shutdown, err := setupTelemetry(context.Background(), "")
+	if err != nil {
+		t.Fatalf("setupTelemetry() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
+
+func TestSetupTelemetry_WithEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	paths := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = true
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	tp, mp := otel.GetTracerProvider(), otel.GetMeterProvider()
+	defer func() {
+		otel.SetTracerProvider(tp)
+		otel.SetMeterProvider(mp)
+	}()
+
+	shutdown, err := setupTelemetry(context.Background(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("setupTelemetry() error = %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, want := range []string{"/v1/traces", "/v1/metrics"} {
+		if !paths[want] {
+			t.Errorf("shutdown() did not export to %s, got %v", want, paths)
+		}
+	}
+}